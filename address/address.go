@@ -0,0 +1,585 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+/*
+Package address is the Chinese postal address parser, split the address
+to the province, city, district, street, building and room by the gse
+segmentation with the administrative division dictionary:
+
+	p := address.New(&seg)
+	err := p.LoadDict("division.txt")
+	a := p.Parse("广东广州市天河区天河路385号太古汇1座1203室")
+
+The division dictionary is the "name level parent" lines, such as
+"广州市 city 广东省", the parents should be loaded before the children,
+the province level divisions are loaded by default.
+*/
+package address
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-ego/gse"
+)
+
+// The address levels
+const (
+	Province = "province"
+	City     = "city"
+	District = "district"
+	Street   = "street"
+	Building = "building"
+	Room     = "room"
+)
+
+var levels = map[string]int{
+	Province: 1, City: 2, District: 3, Street: 4,
+}
+
+// DefaultDivisions the default province level divisions
+var DefaultDivisions = `北京市 province
+天津市 province
+上海市 province
+重庆市 province
+河北省 province
+山西省 province
+辽宁省 province
+吉林省 province
+黑龙江省 province
+江苏省 province
+浙江省 province
+安徽省 province
+福建省 province
+江西省 province
+山东省 province
+河南省 province
+湖北省 province
+湖南省 province
+广东省 province
+海南省 province
+四川省 province
+贵州省 province
+云南省 province
+陕西省 province
+甘肃省 province
+青海省 province
+台湾省 province
+内蒙古自治区 province
+广西壮族自治区 province
+西藏自治区 province
+宁夏回族自治区 province
+新疆维吾尔自治区 province
+香港特别行政区 province
+澳门特别行政区 province
+北京市 city 北京市
+天津市 city 天津市
+上海市 city 上海市
+重庆市 city 重庆市`
+
+// suffixes the division suffixes removed by the abbreviation,
+// the longest first
+var suffixes = []string{
+	"特别行政区", "维吾尔自治区", "壮族自治区", "回族自治区", "自治区",
+	"自治州", "地区", "省", "市", "区", "县", "盟", "旗",
+}
+
+// Division the administrative division
+type Division struct {
+	Name string
+	// Short the abbreviation without the suffix, such as "广东"
+	Short  string
+	Level  string
+	Parent *Division
+}
+
+// Short return the abbreviation of the division name
+func Short(name string) string {
+	for _, s := range suffixes {
+		short := strings.TrimSuffix(name, s)
+		if short != name && utf8.RuneCountInString(short) >= 2 {
+			return short
+		}
+	}
+	return name
+}
+
+// Component the address component
+type Component struct {
+	Level string
+	// Text the text in the address, Name the normalized name,
+	// such as the Text "广东" is the Name "广东省"
+	Text, Name string
+	// Start and End the bytes offsets of the text in the address
+	Start, End int
+	// Inferred the component is omitted in the address and
+	// inferred from the lower level division
+	Inferred   bool
+	Confidence float64
+}
+
+// Address the parsed address
+type Address struct {
+	Province, City, District, Street, Building, Room Component
+	// Detail the text after the divisions, without the punctuations
+	Detail string
+}
+
+// Components return the not empty components by the level
+func (a *Address) Components() (cs []Component) {
+	for _, c := range []Component{a.Province, a.City, a.District,
+		a.Street, a.Building, a.Room} {
+		if c.Name != "" {
+			cs = append(cs, c)
+		}
+	}
+	return
+}
+
+// String return the normalized address
+func (a *Address) String() string {
+	var b strings.Builder
+	for _, c := range a.Components() {
+		if c.Level == City && c.Name == a.Province.Name {
+			continue
+		}
+		b.WriteString(c.Name)
+	}
+	return b.String()
+}
+
+func (a *Address) component(level string) *Component {
+	switch level {
+	case Province:
+		return &a.Province
+	case City:
+		return &a.City
+	case District:
+		return &a.District
+	case Street:
+		return &a.Street
+	case Building:
+		return &a.Building
+	}
+	return &a.Room
+}
+
+// Options the parser options
+type Options struct {
+	// Freq the frequency of the division names added to the segmenter,
+	// default is 1000
+	Freq float64
+}
+
+// Parser the address parser
+type Parser struct {
+	seg *gse.Segmenter
+	opt Options
+
+	// Divisions the divisions by the name and the abbreviation
+	Divisions map[string][]*Division
+	// maxLen the max bytes length of the division names
+	maxLen int
+}
+
+// New create a new Parser with the segmenter and the default divisions
+func New(seg *gse.Segmenter, opts ...Options) *Parser {
+	var opt Options
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if opt.Freq <= 0 {
+		opt.Freq = 1000
+	}
+
+	p := &Parser{seg: seg, opt: opt, Divisions: make(map[string][]*Division)}
+	p.LoadDictStr(DefaultDivisions)
+	return p
+}
+
+// addToken add the word to the segmenter if it is not in the dictionary
+func (p *Parser) addToken(word string) bool {
+	// the prefix of the dictionary words is found with the freq 0
+	if freq, _, ok := p.seg.Find(word); ok && freq > 0 {
+		return false
+	}
+
+	return p.seg.AddToken(word, p.opt.Freq, "ns") == nil
+}
+
+// add add the division without the segmenter CalcToken
+func (p *Parser) add(name, level, parent string) (bool, error) {
+	l, ok := levels[level]
+	if !ok {
+		return false, fmt.Errorf("address: unknown level %q", level)
+	}
+
+	d := &Division{Name: name, Short: Short(name), Level: level}
+	if parent != "" {
+		for _, pd := range p.Divisions[parent] {
+			if pd.Name == parent && levels[pd.Level] < l {
+				d.Parent = pd
+			}
+		}
+		if d.Parent == nil {
+			return false, fmt.Errorf("address: parent %q of %q not found", parent, name)
+		}
+	}
+
+	p.Divisions[name] = append(p.Divisions[name], d)
+	if len(name) > p.maxLen {
+		p.maxLen = len(name)
+	}
+	add := p.addToken(name)
+	if d.Short != name {
+		p.Divisions[d.Short] = append(p.Divisions[d.Short], d)
+		add = p.addToken(d.Short) || add
+	}
+	return add, nil
+}
+
+// AddDivision add the division with the level and the parent name
+func (p *Parser) AddDivision(name, level, parent string) error {
+	add, err := p.add(name, level, parent)
+	if add {
+		p.seg.CalcToken()
+	}
+	return err
+}
+
+// ReadDict read the division dictionary of the "name level parent" lines
+func (p *Parser) ReadDict(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	add := false
+	line := 0
+
+	defer func() {
+		if add {
+			p.seg.CalcToken()
+		}
+	}()
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) < 2 {
+			return fmt.Errorf("address: line %d: not the name level format", line)
+		}
+
+		parent := ""
+		if len(fields) > 2 {
+			parent = fields[2]
+		}
+		ok, err := p.add(fields[0], fields[1], parent)
+		if err != nil {
+			return fmt.Errorf("address: line %d: %v", line, err)
+		}
+		add = ok || add
+	}
+
+	return scanner.Err()
+}
+
+// LoadDictStr load the division dictionary from the string
+func (p *Parser) LoadDictStr(str string) error {
+	return p.ReadDict(strings.NewReader(str))
+}
+
+// LoadDict load the division dictionary files
+func (p *Parser) LoadDict(files ...string) error {
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+
+		err = p.ReadDict(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// clean remove the punctuations and spaces of the text, convert the
+// full width characters, return the text with the start and end bytes
+// offsets in the text of the source rune of every byte
+func clean(text string) (string, []int, []int) {
+	var (
+		b        strings.Builder
+		off, end []int
+	)
+	for i, r := range text {
+		size := utf8.RuneLen(r)
+		if r >= '！' && r <= '～' {
+			r -= '！' - '!'
+		}
+		if r == '-' || r == '#' {
+			// the building and room separators, such as "3-1203"
+		} else if unicode.IsPunct(r) || unicode.IsSpace(r) || unicode.IsSymbol(r) {
+			continue
+		}
+
+		// the folded full width rune ends at the end of the source rune
+		n := utf8.RuneLen(r)
+		for k := 0; k < n; k++ {
+			off = append(off, i+k)
+			if k == n-1 {
+				end = append(end, i+size)
+			} else {
+				end = append(end, i+k+1)
+			}
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String(), off, end
+}
+
+// ancestor the a is the ancestor of the d
+func ancestor(a, d *Division) bool {
+	for p := d.Parent; p != nil; p = p.Parent {
+		if p == a {
+			return true
+		}
+	}
+	return false
+}
+
+// streetSuffix the text is followed by the street suffix,
+// such as the "天河" of the "天河路" is not the district
+func streetSuffix(text string) bool {
+	for _, s := range []string{"路", "街", "大道", "道", "巷", "弄", "胡同"} {
+		if strings.HasPrefix(text, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// choose choose the division of the name after the last division
+func (p *Parser) choose(name, rest string, last *Division) (*Division, float64) {
+	var (
+		best *Division
+		conf float64
+	)
+	for _, d := range p.Divisions[name] {
+		if last != nil && levels[d.Level] <= levels[last.Level] {
+			continue
+		}
+
+		c := 1.0
+		if name != d.Name {
+			if streetSuffix(rest) {
+				continue
+			}
+			c = 0.9
+		}
+		if last != nil && !ancestor(last, d) {
+			c *= 0.5
+		}
+
+		if best == nil || c > conf || (c == conf && levels[d.Level] < levels[best.Level]) {
+			best, conf = d, c
+		}
+	}
+
+	if best != nil && last == nil {
+		// the same name of the other divisions without the parent
+		for _, d := range p.Divisions[name] {
+			if d != best && d.Level == best.Level {
+				conf *= 0.6
+				break
+			}
+		}
+	}
+	return best, conf
+}
+
+// prefix return the longest division name which is the prefix of the text
+func (p *Parser) prefix(text string) string {
+	name := ""
+	for i := range text {
+		if i > p.maxLen {
+			break
+		}
+		if _, ok := p.Divisions[text[:i]]; ok {
+			name = text[:i]
+		}
+	}
+	if _, ok := p.Divisions[text]; ok && len(text) <= p.maxLen {
+		name = text
+	}
+	return name
+}
+
+// Parse parse the address
+func (p *Parser) Parse(text string) (a Address) {
+	str, off, offEnd := clean(text)
+	words := p.seg.Cut(str)
+
+	var (
+		last  *Division
+		pos   int
+		found = false
+	)
+
+	detail := 0
+	for k := 0; k < len(words); k++ {
+		start := pos
+		matched := false
+		// join the words cut apart, such as "广东" and "省"
+		for n := 3; n >= 1; n-- {
+			if k+n > len(words) {
+				continue
+			}
+
+			name := strings.Join(words[k:k+n], "")
+			end := start + len(name)
+			d, conf := p.choose(name, str[end:], last)
+			if d == nil {
+				continue
+			}
+
+			*a.component(d.Level) = Component{Level: d.Level, Text: name, Name: d.Name,
+				Start: off[start], End: offEnd[end-1], Confidence: conf}
+			last, found, matched = d, true, true
+			pos, detail = end, end
+			k += n - 1
+			break
+		}
+
+		if !matched {
+			// the division name inside the word, such as "天河区" of "天河区政府"
+			if name := p.prefix(str[start:]); name != "" {
+				end := start + len(name)
+				if d, conf := p.choose(name, str[end:], last); d != nil {
+					*a.component(d.Level) = Component{Level: d.Level, Text: name, Name: d.Name,
+						Start: off[start], End: offEnd[end-1], Confidence: conf}
+					last, found = d, true
+					pos, detail = end, end
+
+					// split the word crossing the division end
+					for w := start; k < len(words) && w < end; k++ {
+						if w+len(words[k]) > end {
+							words[k] = words[k][end-w:]
+							break
+						}
+						w += len(words[k])
+					}
+					k--
+					continue
+				}
+			}
+
+			if found {
+				break
+			}
+			pos += len(words[k])
+		}
+	}
+
+	// the omitted levels
+	if last != nil {
+		for d := last.Parent; d != nil; d = d.Parent {
+			c := a.component(d.Level)
+			if c.Name == "" {
+				*c = Component{Level: d.Level, Name: d.Name, Inferred: true, Confidence: 0.8}
+			}
+		}
+	}
+
+	a.Detail = str[detail:]
+	p.detail(&a, str, detail, off, offEnd)
+	return
+}
+
+var (
+	reStreet = regexp.MustCompile(
+		`^.*?(街道|大道|路|街|巷|弄|胡同|镇|乡|村|道)(\d+(号院|号|弄))?`)
+	reRoom     = regexp.MustCompile(`[A-Za-z]?\d+[A-Za-z]?(室|房|户)$`)
+	reDashRoom = regexp.MustCompile(`(\d+)[-#](\d+)$`)
+	reBuilding = regexp.MustCompile(`(栋|幢|号楼|座|楼|单元|大厦|公寓|小区|花园|苑|广场|中心)$`)
+	reNumber   = regexp.MustCompile(`\d{3,4}$`)
+)
+
+// detail parse the street, building and room of the text after the divisions
+func (p *Parser) detail(a *Address, str string, start int, off, offEnd []int) {
+	set := func(level string, s, e int, conf float64) {
+		if s >= e {
+			return
+		}
+		*a.component(level) = Component{Level: level, Text: str[s:e], Name: str[s:e],
+			Start: off[s], End: offEnd[e-1], Confidence: conf}
+	}
+
+	// the streets from the start, such as "石牌街道天河路385号"
+	end := start
+	for {
+		loc := reStreet.FindStringSubmatchIndex(str[end:])
+		if loc == nil || loc[1] == 0 {
+			break
+		}
+
+		conf := 0.8
+		if loc[4] >= 0 {
+			conf = 0.9
+		}
+		if a.Street.Name != "" && end > 0 && a.Street.End == offEnd[end-1] {
+			a.Street.Text += str[end : end+loc[1]]
+			a.Street.Name += str[end : end+loc[1]]
+			a.Street.End = offEnd[end+loc[1]-1]
+		} else {
+			set(Street, end, end+loc[1], conf)
+		}
+		end += loc[1]
+	}
+
+	// the room from the end
+	rest := str[end:]
+	bEnd := len(str)
+	if loc := reRoom.FindStringIndex(rest); loc != nil {
+		set(Room, end+loc[0], len(str), 0.9)
+		bEnd = end + loc[0]
+	} else if m := reDashRoom.FindStringSubmatchIndex(rest); m != nil {
+		set(Room, end+m[4], len(str), 0.7)
+		bEnd = end + m[3]
+	} else if loc := reNumber.FindStringIndex(rest); loc != nil &&
+		reBuilding.MatchString(rest[:loc[0]]) {
+		set(Room, end+loc[0], len(str), 0.7)
+		bEnd = end + loc[0]
+	}
+
+	conf := 0.5
+	if reBuilding.MatchString(str[end:bEnd]) {
+		conf = 0.9
+	} else if bEnd > end && bEnd < len(str) && str[bEnd-1] >= '0' && str[bEnd-1] <= '9' {
+		// the building number of the "5-1203"
+		conf = 0.6
+	}
+	set(Building, end, bEnd, conf)
+}
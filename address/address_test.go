@@ -0,0 +1,121 @@
+package address
+
+import (
+	"testing"
+
+	"github.com/go-ego/gse"
+	"github.com/vcaesar/tt"
+)
+
+const divisions = `广州市 city 广东省
+天河区 district 广州市
+越秀区 district 广州市
+海淀区 district 北京市
+朝阳区 district 北京市
+长春市 city 吉林省
+朝阳区 district 长春市
+石牌街道 street 天河区`
+
+func newParser() *Parser {
+	var seg gse.Segmenter
+	seg.SkipLog = true
+	seg.LoadDictStr(`的 1000 uj
+路 100 n
+号 100 m
+太古汇 100 nz`)
+
+	p := New(&seg)
+	p.LoadDictStr(divisions)
+	return p
+}
+
+func TestParse(t *testing.T) {
+	p := newParser()
+	text := "广东省广州市天河区天河路385号太古汇1座1203室"
+	a := p.Parse(text)
+	tt.Equal(t, "广东省", a.Province.Name)
+	tt.Equal(t, "广州市", a.City.Name)
+	tt.Equal(t, "天河区", a.District.Name)
+	tt.Equal(t, "天河路385号", a.Street.Name)
+	tt.Equal(t, "太古汇1座", a.Building.Name)
+	tt.Equal(t, "1203室", a.Room.Name)
+	tt.Equal(t, 1, a.District.Confidence)
+	tt.Equal(t, "天河路385号", text[a.Street.Start:a.Street.End])
+	tt.Equal(t, 6, len(a.Components()))
+	tt.Equal(t, text, a.String())
+}
+
+func TestOmitted(t *testing.T) {
+	p := newParser()
+	text := "收货地址：广州 天河区，石牌街道天河路8号 5-1203"
+	a := p.Parse(text)
+	tt.Equal(t, "广东省", a.Province.Name)
+	tt.True(t, a.Province.Inferred)
+	tt.Equal(t, "广州", a.City.Text)
+	tt.Equal(t, "广州市", a.City.Name)
+	tt.Equal(t, 0.9, a.City.Confidence)
+	tt.Equal(t, "广州", text[a.City.Start:a.City.End])
+	tt.Equal(t, "石牌街道天河路8号", a.Street.Name)
+	tt.Equal(t, "5", a.Building.Name)
+	tt.Equal(t, "1203", a.Room.Name)
+
+	a = p.Parse("北京海淀区中关村大街27号")
+	tt.Equal(t, "北京市", a.Province.Name)
+	tt.Equal(t, "北京市", a.City.Name)
+	tt.Equal(t, "中关村大街27号", a.Street.Name)
+	tt.Equal(t, "北京市海淀区中关村大街27号", a.String())
+
+	a = p.Parse("朝阳区建国路")
+	tt.Equal(t, 0.6, a.District.Confidence)
+	a = p.Parse("吉林长春朝阳区")
+	tt.Equal(t, "长春市", a.City.Name)
+	tt.Equal(t, 0.9, a.City.Confidence)
+	tt.Equal(t, 1, a.District.Confidence)
+
+	tt.NotNil(t, p.AddDivision("某区", "district", "不存在市"))
+	tt.NotNil(t, p.LoadDictStr("某区 town"))
+	tt.Equal(t, "黑龙江", Short("黑龙江省"))
+	tt.Equal(t, "沙县", Short("沙县"))
+}
+
+func TestRoomOnly(t *testing.T) {
+	p := newParser()
+	a := p.Parse("1203室")
+	tt.Equal(t, "1203室", a.Room.Name)
+	tt.Equal(t, "", a.Building.Name)
+	tt.Equal(t, "2室", p.Parse("2室").Room.Name)
+}
+
+func TestFullWidth(t *testing.T) {
+	p := newParser()
+	text := "广州天河区天河路８号５－１２０３"
+	a := p.Parse(text)
+	tt.Equal(t, "天河路8号", a.Street.Name)
+	tt.Equal(t, "天河路８号", text[a.Street.Start:a.Street.End])
+	tt.Equal(t, "5", a.Building.Name)
+	tt.Equal(t, "５", text[a.Building.Start:a.Building.End])
+	tt.Equal(t, "1203", a.Room.Name)
+	tt.Equal(t, "１２０３", text[a.Room.Start:a.Room.End])
+}
+
+func TestCompound(t *testing.T) {
+	var seg gse.Segmenter
+	seg.SkipLog = true
+	seg.LoadDictStr(`的 1000 uj
+天河区政府 5000 nt
+大楼 100 n`)
+
+	p := New(&seg)
+	p.LoadDictStr(divisions)
+	freq, _, ok := seg.Find("天河区")
+	tt.True(t, ok)
+	tt.Equal(t, 1000, freq)
+
+	text := "广东省广州市天河区政府大楼"
+	a := p.Parse(text)
+	tt.Equal(t, "广州市", a.City.Name)
+	tt.Equal(t, "天河区", a.District.Name)
+	tt.Equal(t, "天河区", text[a.District.Start:a.District.End])
+	tt.Equal(t, "政府大楼", a.Detail)
+	tt.NotEqual(t, "天河区政府大楼", a.Building.Name)
+}
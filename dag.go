@@ -37,12 +37,25 @@ type route struct {
 
 // Find find word in dictionary return word's freq, pos and existence
 func (seg *Segmenter) Find(str string) (float64, string, bool) {
-	return seg.Dict.Find([]byte(str))
+	return seg.find([]byte(str))
 }
 
-// Value find word in dictionary return word's value
+// Value find word in the dictionary and layers return word's value,
+// the value is the token index in the dictionary or layer having the word,
+// use FindToken to get the token
 func (seg *Segmenter) Value(str string) (int, int, error) {
-	return seg.Dict.Value([]byte(str))
+	_, val, id, err := seg.value([]byte(str))
+	return val, id, err
+}
+
+// FindToken find word in the dictionary and layers return the token
+func (seg *Segmenter) FindToken(str string) (*Token, bool) {
+	dict, val, _, err := seg.value([]byte(str))
+	if err != nil || !dict.IsLive(val) {
+		return nil, false
+	}
+
+	return &dict.Tokens[val], true
 }
 
 // FindAllOccs find the all search byte start in data
@@ -368,6 +381,9 @@ func (seg *Segmenter) cutForSearch(str string, hmm ...bool) []string {
 		}
 
 		result = append(result, word)
+		if seg.Synonym {
+			result = append(result, seg.Synonyms(word)...)
+		}
 	}
 
 	return result
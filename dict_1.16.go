@@ -12,6 +12,7 @@ package gse
 
 import (
 	"strings"
+	"time"
 )
 
 // //go:embed data/dict/dictionary.txt
@@ -94,6 +95,9 @@ func (seg *Segmenter) LoadDictStr(dict string) error {
 		seg.Init()
 	}
 
+	start := time.Now()
+	num := seg.Dict.NumTokens()
+
 	arr := strings.Split(dict, "\n")
 	for i := 0; i < len(arr); i++ {
 		s1 := strings.Split(arr[i], seg.DictSep+" ")
@@ -125,6 +129,8 @@ func (seg *Segmenter) LoadDictStr(dict string) error {
 	}
 
 	seg.CalcToken()
+	seg.Log().Debug("gse dictionary string loaded", "lines", len(arr),
+		"tokens", seg.Dict.NumTokens()-num, "duration", time.Since(start))
 	return nil
 }
 
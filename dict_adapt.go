@@ -0,0 +1,218 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package gse
+
+import (
+	"math"
+	"time"
+)
+
+// AdaptOptions the options of the dictionary frequency re-estimation
+type AdaptOptions struct {
+	// Iterations the number of the EM iterations, default is 5
+	Iterations int
+	// Lambda the interpolation weight of the base dictionary frequency,
+	// 0 is only use the frequency estimated from the corpus
+	Lambda float64
+	// Alpha the additive smoothing of the expected counts, default is 0.01
+	Alpha float64
+}
+
+// the log probability of a character not in the dictionary,
+// the same as the pseudo token distance in segmentWords
+var logUnknown = -32 * math.Ln2
+
+type latticeEdge struct {
+	start, end int
+	// the token index in the dictionary, -1 is the unknown character
+	index int
+}
+
+func logAdd(a, b float64) float64 {
+	if math.IsInf(a, -1) {
+		return b
+	}
+	if math.IsInf(b, -1) {
+		return a
+	}
+
+	if a < b {
+		a, b = b, a
+	}
+	return a + math.Log1p(math.Exp(b-a))
+}
+
+// lattice return all the dictionary tokens in the text as the edges
+func (seg *Segmenter) lattice(text []Text) (edges []latticeEdge) {
+	dict := seg.Dict
+	for i := range text {
+		single := false
+		id := 0
+		for j := i; j < len(text) && j-i < dict.maxTokenLen; j++ {
+			var err error
+			id, err = dict.trie.Jump(text[j], id)
+			if err != nil {
+				break
+			}
+
+			value, err := dict.trie.Value(id)
+			if err == nil && dict.Tokens[value].freq > 0 {
+				edges = append(edges, latticeEdge{start: i, end: j + 1, index: value})
+				single = single || j == i
+			}
+		}
+
+		if !single {
+			edges = append(edges, latticeEdge{start: i, end: i + 1, index: -1})
+		}
+	}
+
+	return
+}
+
+// expect add the expected counts of the tokens in the text lattice
+// by the forward-backward algorithm, return the log likelihood
+func expect(edges []latticeEdge, n int, logP, counts []float64) float64 {
+	alpha := make([]float64, n+1)
+	beta := make([]float64, n+1)
+	for i := range alpha {
+		alpha[i] = math.Inf(-1)
+		beta[i] = math.Inf(-1)
+	}
+	alpha[0], beta[n] = 0, 0
+
+	lp := func(e latticeEdge) float64 {
+		if e.index < 0 {
+			return logUnknown
+		}
+		return logP[e.index]
+	}
+
+	// the edges are sorted by start
+	for _, e := range edges {
+		alpha[e.end] = logAdd(alpha[e.end], alpha[e.start]+lp(e))
+	}
+	for k := len(edges) - 1; k >= 0; k-- {
+		e := edges[k]
+		beta[e.start] = logAdd(beta[e.start], lp(e)+beta[e.end])
+	}
+
+	z := alpha[n]
+	for _, e := range edges {
+		if e.index >= 0 {
+			counts[e.index] += math.Exp(alpha[e.start] + lp(e) + beta[e.end] - z)
+		}
+	}
+
+	return z
+}
+
+// Adapt re-estimates the dictionary frequencies on the domain corpus,
+// it uses the EM over the segmentation lattice with the current dictionary,
+// the expected counts of all the possible segmentations are used
+// rather than the counts of the shortest path.
+//
+// It returns the adapted dictionary with the same tokens and about the same
+// total frequency, can be saved by WriteTo and loaded by LoadDict directly.
+func (seg *Segmenter) Adapt(texts []string, opts ...AdaptOptions) *Dictionary {
+	var opt AdaptOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if opt.Iterations <= 0 {
+		opt.Iterations = 5
+	}
+	if opt.Alpha <= 0 {
+		opt.Alpha = 0.01
+	}
+
+	var (
+		dict  = seg.Dict
+		num   = len(dict.Tokens)
+		total = dict.totalFreq
+
+		lens    = make([]int, 0, len(texts))
+		lattice = make([][]latticeEdge, 0, len(texts))
+	)
+
+	for _, text := range texts {
+		words := seg.SplitTextToWords([]byte(text))
+		if len(words) > 0 {
+			lens = append(lens, len(words))
+			lattice = append(lattice, seg.lattice(words))
+		}
+	}
+
+	freqs := make([]float64, num)
+	for i := range dict.Tokens {
+		if dict.IsLive(i) {
+			freqs[i] = dict.Tokens[i].freq
+		}
+	}
+
+	logP := make([]float64, num)
+	counts := make([]float64, num)
+	for iter := 0; iter < opt.Iterations; iter++ {
+		start := time.Now()
+		for i := range freqs {
+			logP[i] = math.Log(freqs[i] / total)
+			counts[i] = 0
+		}
+
+		logLike := 0.0
+		for k, edges := range lattice {
+			logLike += expect(edges, lens[k], logP, counts)
+		}
+
+		sum := 0.0
+		live := 0
+		for i := range counts {
+			if freqs[i] > 0 {
+				sum += counts[i] + opt.Alpha
+				live++
+			}
+		}
+
+		for i := range freqs {
+			if freqs[i] <= 0 {
+				continue
+			}
+
+			p := (counts[i] + opt.Alpha) / sum
+			if opt.Lambda > 0 {
+				p = opt.Lambda*dict.Tokens[i].freq/total + (1-opt.Lambda)*p
+			}
+			freqs[i] = p * total
+		}
+
+		seg.Log().Info("gse dictionary adapt iteration", "iteration", iter+1,
+			"log_likelihood", logLike, "tokens", live, "duration", time.Since(start))
+	}
+
+	minFreq := seg.MinTokenFreq
+	adapted := NewDict()
+	for i := range dict.Tokens {
+		if freqs[i] <= 0 {
+			continue
+		}
+
+		token := dict.Tokens[i]
+		token.freq = math.Round(math.Max(freqs[i], minFreq))
+		token.segments = nil
+		adapted.AddToken(token)
+	}
+
+	return adapted
+}
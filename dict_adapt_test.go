@@ -0,0 +1,44 @@
+package gse
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/vcaesar/tt"
+)
+
+func TestAdapt(t *testing.T) {
+	var seg1 Segmenter
+	seg1.SkipLog = true
+	err := seg1.LoadDictStr("机器 1000 n\n学习 1000 v\n机器学习 100 n\n" +
+		"平台 500 n\n机 200 n\n器 200 n\n学 200 v\n习 200 v")
+	tt.Nil(t, err)
+	tt.Equal(t, "[机器 学习 平台]", seg1.Cut("机器学习平台", false))
+
+	texts := []string{
+		"机器学习平台", "机器学习", "机器学习，学习机器", "平台的机器学习",
+	}
+	dict := seg1.Adapt(texts, AdaptOptions{Iterations: 5})
+	tt.Equal(t, seg1.Dict.NumTokens(), dict.NumTokens())
+
+	freq, pos, ok := dict.Find([]byte("机器学习"))
+	tt.Bool(t, ok)
+	tt.Equal(t, "n", pos)
+	tt.True(t, freq > 1000)
+
+	var buf bytes.Buffer
+	_, err = dict.WriteTo(&buf)
+	tt.Nil(t, err)
+
+	var seg2 Segmenter
+	seg2.SkipLog = true
+	err = seg2.LoadDictStr(buf.String())
+	tt.Nil(t, err)
+	tt.Equal(t, "[机器学习 平台]", seg2.Cut("机器学习平台", false))
+	tt.Equal(t, "机器学习/n 平台/n ", seg2.String("机器学习平台"))
+
+	mixed := seg1.Adapt(texts, AdaptOptions{Iterations: 5, Lambda: 0.9})
+	f1, _, _ := mixed.Find([]byte("机器学习"))
+	tt.True(t, f1 < freq)
+	tt.True(t, f1 > 100)
+}
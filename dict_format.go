@@ -0,0 +1,314 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package gse
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DictFormat the dictionary file format
+type DictFormat string
+
+const (
+	// FormatGse the gse format: "text freq pos", separated by seg.DictSep
+	FormatGse DictFormat = "gse"
+	// FormatJieba the jieba user dictionary format: "text [freq] [pos]"
+	FormatJieba DictFormat = "jieba"
+	// FormatTSV the tab separated values with a header naming the columns
+	FormatTSV DictFormat = "tsv"
+	// FormatCSV the comma separated values with a header naming the columns
+	FormatCSV DictFormat = "csv"
+	// FormatJSONL the JSON Lines format, one token object for each line
+	FormatJSONL DictFormat = "jsonl"
+)
+
+var (
+	textKeys = []string{"text", "word"}
+	freqKeys = []string{"freq", "frequency", "count"}
+	posKeys  = []string{"pos", "tag"}
+)
+
+// DictFormatOf get the dictionary format and file name,
+// the format can be specified by the prefix, such as "jieba:user.txt",
+// otherwise it is detected by the file extension (.tsv, .csv, .jsonl),
+// the default format is FormatGse
+func DictFormatOf(file string) (DictFormat, string) {
+	if i := strings.Index(file, ":"); i > 0 {
+		format := DictFormat(strings.ToLower(file[:i]))
+		switch format {
+		case FormatGse, FormatJieba, FormatTSV, FormatCSV, FormatJSONL:
+			return format, file[i+1:]
+		}
+	}
+
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".tsv":
+		return FormatTSV, file
+	case ".csv":
+		return FormatCSV, file
+	case ".jsonl", ".ndjson":
+		return FormatJSONL, file
+	}
+
+	return FormatGse, file
+}
+
+// ReadFormat load the dictionary from io.Reader with the format
+func (seg *Segmenter) ReadFormat(reader io.Reader, format DictFormat, files ...string) error {
+	var file string
+	if len(files) > 0 {
+		file = files[0]
+	}
+
+	switch format {
+	case FormatGse, "":
+		return seg.Reader(bufio.NewReader(reader), file)
+	case FormatJieba:
+		return seg.readJieba(reader)
+	case FormatTSV:
+		return seg.readColumns(reader, '\t', file)
+	case FormatCSV:
+		return seg.readColumns(reader, ',', file)
+	case FormatJSONL:
+		return seg.readJSONL(reader, file)
+	}
+
+	return fmt.Errorf("gse: unknown dictionary format %q", format)
+}
+
+// addText add the text with the freq and pos to the dictionary,
+// use the seg.TextFreq when not specified freq
+func (seg *Segmenter) addText(text, freqText, pos string) {
+	if freqText == "" {
+		freqText = seg.TextFreq
+	}
+
+	freq := seg.Size(2, text, freqText)
+	if freq == 0.0 || text == "" {
+		return
+	}
+
+	words := seg.SplitTextToWords([]byte(text))
+	token := Token{text: words, freq: freq, pos: pos}
+	seg.Dict.AddToken(token)
+}
+
+func (seg *Segmenter) readJieba(reader io.Reader) error {
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		parts := strings.Fields(scanner.Text())
+		if len(parts) == 0 {
+			continue
+		}
+
+		var freqText, pos string
+		if len(parts) > 1 {
+			if _, err := strconv.ParseFloat(parts[1], 64); err == nil {
+				freqText = parts[1]
+				if len(parts) > 2 {
+					pos = parts[2]
+				}
+			} else {
+				pos = parts[1]
+			}
+		}
+
+		seg.addText(parts[0], freqText, pos)
+	}
+
+	return scanner.Err()
+}
+
+func columnIndex(header []string, keys []string) int {
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(h))
+		for _, k := range keys {
+			if h == k {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+func (seg *Segmenter) readColumns(reader io.Reader, comma rune, file string) error {
+	r := csv.NewReader(reader)
+	r.Comma = comma
+	r.LazyQuotes = true
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err != nil {
+		return err
+	}
+
+	iText := columnIndex(header, textKeys)
+	if iText < 0 {
+		return errors.New("gse: the dictionary header has no text column")
+	}
+	iFreq := columnIndex(header, freqKeys)
+	iPos := columnIndex(header, posKeys)
+
+	column := func(record []string, i int) string {
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	line := 1
+	for {
+		line++
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			seg.Log().Warn("dict line read error, skip",
+				"file", file, "line", line, "error", err)
+			continue
+		}
+
+		seg.addText(column(record, iText),
+			column(record, iFreq), column(record, iPos))
+	}
+
+	return nil
+}
+
+func jsonValue(m map[string]interface{}, keys []string) string {
+	for _, k := range keys {
+		switch v := m[k].(type) {
+		case string:
+			return strings.TrimSpace(v)
+		case json.Number:
+			return v.String()
+		}
+	}
+
+	return ""
+}
+
+func (seg *Segmenter) readJSONL(reader io.Reader, file string) error {
+	scanner := bufio.NewScanner(reader)
+	line := 0
+	for scanner.Scan() {
+		line++
+		b := bytes.TrimSpace(scanner.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+
+		m := make(map[string]interface{})
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		if err := dec.Decode(&m); err != nil {
+			seg.Log().Warn("dict line read error, skip",
+				"file", file, "line", line, "error", err)
+			continue
+		}
+
+		seg.addText(jsonValue(m, textKeys),
+			jsonValue(m, freqKeys), jsonValue(m, posKeys))
+	}
+
+	return scanner.Err()
+}
+
+type jsonToken struct {
+	Text string  `json:"text"`
+	Freq float64 `json:"freq"`
+	Pos  string  `json:"pos,omitempty"`
+}
+
+func formatFreq(freq float64) string {
+	return strconv.FormatFloat(freq, 'f', -1, 64)
+}
+
+// Export write the live dictionary tokens sorted by text to w with the format,
+// the sep is the separator of the FormatGse like seg.DictSep
+func (dict *Dictionary) Export(w io.Writer, format DictFormat, sep ...string) error {
+	tokens := dict.LiveTokens()
+
+	switch format {
+	case FormatGse, "", FormatJieba:
+		var s string
+		if len(sep) > 0 && format != FormatJieba {
+			s = sep[0]
+		}
+		s += " "
+
+		bw := bufio.NewWriter(w)
+		for _, token := range tokens {
+			line := token.Text() + s + formatFreq(token.freq)
+			if token.pos != "" {
+				line += s + token.pos
+			}
+
+			if _, err := bw.WriteString(line + "\n"); err != nil {
+				return err
+			}
+		}
+		return bw.Flush()
+
+	case FormatTSV, FormatCSV:
+		cw := csv.NewWriter(w)
+		if format == FormatTSV {
+			cw.Comma = '\t'
+		}
+
+		if err := cw.Write([]string{"text", "freq", "pos"}); err != nil {
+			return err
+		}
+		for _, token := range tokens {
+			err := cw.Write([]string{token.Text(), formatFreq(token.freq), token.pos})
+			if err != nil {
+				return err
+			}
+		}
+
+		cw.Flush()
+		return cw.Error()
+
+	case FormatJSONL:
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		for _, token := range tokens {
+			err := enc.Encode(jsonToken{Text: token.Text(), Freq: token.freq, Pos: token.pos})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("gse: unknown dictionary format %q", format)
+}
+
+// ExportDict write the segmenter dictionary to w with the format
+func (seg *Segmenter) ExportDict(w io.Writer, format DictFormat) error {
+	return seg.Dict.Export(w, format, seg.DictSep)
+}
@@ -0,0 +1,147 @@
+package gse
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/vcaesar/tt"
+)
+
+func TestDictFormatOf(t *testing.T) {
+	f, name := DictFormatOf("jieba:user.txt")
+	tt.Equal(t, FormatJieba, f)
+	tt.Equal(t, "user.txt", name)
+
+	f, name = DictFormatOf("data/dict.TSV")
+	tt.Equal(t, FormatTSV, f)
+	tt.Equal(t, "data/dict.TSV", name)
+
+	f, _ = DictFormatOf("a.jsonl")
+	tt.Equal(t, FormatJSONL, f)
+	f, _ = DictFormatOf("a.csv")
+	tt.Equal(t, FormatCSV, f)
+
+	f, name = DictFormatOf("C:/dict/user.txt")
+	tt.Equal(t, FormatGse, f)
+	tt.Equal(t, "C:/dict/user.txt", name)
+}
+
+func TestLoadDictFormat(t *testing.T) {
+	var seg1 Segmenter
+	seg1.SkipLog = true
+	err := seg1.LoadDict("jieba:testdata/format/jieba.txt, " +
+		"testdata/format/dict.tsv, testdata/format/dict.csv, testdata/format/dict.jsonl")
+	tt.Nil(t, err)
+	tt.Equal(t, 11, seg1.Dict.NumTokens())
+
+	f, pos, ok := seg1.Find("创新办")
+	tt.Bool(t, ok)
+	tt.Equal(t, "i", pos)
+	tt.Equal(t, 3, f)
+
+	f, pos, ok = seg1.Find("凱特琳")
+	tt.Bool(t, ok)
+	tt.Equal(t, "nz", pos)
+	tt.Equal(t, 2, f)
+
+	f, pos, _ = seg1.Find("机器学习")
+	tt.Equal(t, "n", pos)
+	tt.Equal(t, 120, f)
+	f, _, ok = seg1.Find("大模型")
+	tt.Bool(t, ok)
+	tt.Equal(t, 2, f)
+
+	f, _, ok = seg1.Find("数据,平台")
+	tt.Bool(t, ok)
+	tt.Equal(t, 30, f)
+
+	f, pos, _ = seg1.Find("向量数据库")
+	tt.Equal(t, "n", pos)
+	tt.Equal(t, 25, f)
+	f, _, ok = seg1.Find("提示词")
+	tt.Bool(t, ok)
+	tt.Equal(t, 12, f)
+
+	err = seg1.ReadFormat(strings.NewReader("a b\n"), "xml")
+	tt.NotNil(t, err)
+	err = seg1.ReadFormat(strings.NewReader("name\tfreq\n"), FormatTSV)
+	tt.NotNil(t, err)
+}
+
+func TestExportDict(t *testing.T) {
+	var seg1 Segmenter
+	seg1.SkipLog = true
+	err := seg1.LoadDict("testdata/format/dict.tsv")
+	tt.Nil(t, err)
+
+	var buf bytes.Buffer
+	err = seg1.ExportDict(&buf, FormatGse)
+	tt.Nil(t, err)
+	tt.Equal(t, "大模型 2\n机器学习 120 n\n深度学习 80 n\n", buf.String())
+
+	for _, format := range []DictFormat{FormatJieba, FormatTSV, FormatCSV, FormatJSONL} {
+		buf.Reset()
+		err = seg1.ExportDict(&buf, format)
+		tt.Nil(t, err)
+
+		var seg2 Segmenter
+		seg2.Dict = NewDict()
+		seg2.Init()
+		err = seg2.ReadFormat(&buf, format)
+		tt.Nil(t, err)
+
+		buf.Reset()
+		seg2.Dict.Export(&buf, FormatGse)
+		tt.Equal(t, "大模型 2\n机器学习 120 n\n深度学习 80 n\n", buf.String())
+	}
+
+	buf.Reset()
+	seg1.Dict.Export(&buf, FormatJSONL)
+	tt.Equal(t, `{"text":"机器学习","freq":120,"pos":"n"}`, strings.Split(buf.String(), "\n")[1])
+}
+
+func TestSaveDict(t *testing.T) {
+	var seg1 Segmenter
+	seg1.SkipLog = true
+	err := seg1.LoadDict("testdata/format/dict.tsv")
+	tt.Nil(t, err)
+
+	err = seg1.AddToken("知识库", 30, "n")
+	tt.Nil(t, err)
+	err = seg1.RemoveToken("深度学习")
+	tt.Nil(t, err)
+	err = seg1.ReAddToken("机器学习", 150, "nz")
+	tt.Nil(t, err)
+
+	tt.False(t, seg1.Dict.IsLive(1))
+	tt.True(t, seg1.Dict.IsLive(3))
+	tt.Equal(t, 3, len(seg1.Dict.LiveTokens()))
+
+	var buf bytes.Buffer
+	n, err := seg1.Dict.WriteTo(&buf)
+	tt.Nil(t, err)
+	tt.Equal(t, "大模型 2\n机器学习 150 nz\n知识库 30 n\n", buf.String())
+	tt.Equal(t, buf.Len(), n)
+
+	file := t.TempDir() + "/dict.txt"
+	err = seg1.SaveDict(file)
+	tt.Nil(t, err)
+
+	var seg2 Segmenter
+	seg2.SkipLog = true
+	err = seg2.LoadDict(file)
+	tt.Nil(t, err)
+	tt.Equal(t, 3, seg2.Dict.NumTokens())
+
+	f, pos, ok := seg2.Find("机器学习")
+	tt.Bool(t, ok)
+	tt.Equal(t, "nz", pos)
+	tt.Equal(t, 150, f)
+
+	_, _, ok = seg2.Find("深度学习")
+	tt.False(t, ok)
+
+	err = seg1.SaveDict(t.TempDir() + "/not/exist.txt")
+	tt.NotNil(t, err)
+}
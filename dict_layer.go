@@ -0,0 +1,274 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package gse
+
+import (
+	"math"
+	"path"
+	"sort"
+)
+
+// Layer a dictionary layer over the segmenter dictionary,
+// such as the domain, tenant or request dictionary.
+//
+// The layers and the segmenter dictionary (priority 0) are consulted together,
+// when a word is in several layers, the highest priority one is used.
+type Layer struct {
+	Name     string
+	Priority int
+	// Scale scale the frequency of the layer tokens
+	Scale float64
+
+	Dict *Dictionary
+	seg  *Segmenter
+}
+
+// NewLayer create a new empty dictionary layer of the segmenter,
+// use seg.AddLayer or seg.WithLayers to consult it when segmenting
+func (seg *Segmenter) NewLayer(name string, priority int, scale ...float64) *Layer {
+	l := &Layer{Name: name, Priority: priority, Scale: 1.0, Dict: NewDict(), seg: seg}
+	if len(scale) > 0 && scale[0] > 0 {
+		l.Scale = scale[0]
+	}
+
+	return l
+}
+
+// segmenter return a segmenter copy working on the layer dictionary
+func (l *Layer) segmenter() *Segmenter {
+	s := *l.seg
+	s.Dict = l.Dict
+	s.layers = nil
+	s.NotLoadHMM = true
+	s.Init()
+	return &s
+}
+
+// AddToken add a new text to the layer
+func (l *Layer) AddToken(text string, freq float64, pos ...string) error {
+	token := l.seg.ToToken(text, freq, pos...)
+	num := l.Dict.NumTokens()
+	err := l.Dict.AddToken(token)
+	if err != nil || l.Dict.NumTokens() == num {
+		return err
+	}
+
+	l.calcToken(&l.Dict.Tokens[num])
+	return nil
+}
+
+// RemoveToken remove the token in the layer
+func (l *Layer) RemoveToken(text string) error {
+	words := l.seg.SplitTextToWords([]byte(text))
+	return l.Dict.RemoveToken(Token{text: words})
+}
+
+// LoadDict load the dictionary files to the layer, the same as seg.LoadDict
+func (l *Layer) LoadDict(files ...string) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	s := l.segmenter()
+	dictDir := path.Join(path.Dir(s.GetCurrentFilePath()), "data")
+	for _, file := range DictPaths(dictDir, files[0]) {
+		if err := s.Read(file); err != nil {
+			return err
+		}
+	}
+
+	l.Calc()
+	return nil
+}
+
+// LoadDictStr load the dictionary string to the layer
+func (l *Layer) LoadDictStr(dict string) error {
+	err := l.segmenter().LoadDictStr(dict)
+	l.Calc()
+	return err
+}
+
+// Calc calculate the path value of the layer tokens,
+// relative to the total frequency of the segmenter dictionary
+func (l *Layer) Calc() {
+	for i := range l.Dict.Tokens {
+		l.calcToken(&l.Dict.Tokens[i])
+	}
+}
+
+func (l *Layer) calcToken(token *Token) {
+	total := l.Dict.totalFreq
+	if l.seg.Dict != nil && l.seg.Dict.totalFreq > 0 {
+		total = l.seg.Dict.totalFreq
+	}
+
+	token.distance = float32(math.Log2(total) - math.Log2(token.freq*l.Scale))
+	if l.seg.Dict != nil {
+		segments := l.seg.segmentWords(token.text, true)
+		token.segments = make([]*Segment, len(segments))
+		for i := range segments {
+			token.segments[i] = &segments[i]
+		}
+	}
+}
+
+// AddLayer add the layers to the segmenter,
+// it is not safe to call with segmenting concurrently
+func (seg *Segmenter) AddLayer(layers ...*Layer) {
+	seg.layers = append(seg.layers, layers...)
+	sort.SliceStable(seg.layers, func(i, j int) bool {
+		return seg.layers[i].Priority < seg.layers[j].Priority
+	})
+}
+
+// RemoveLayer remove the layer by name from the segmenter
+func (seg *Segmenter) RemoveLayer(name string) {
+	layers := make([]*Layer, 0, len(seg.layers))
+	for _, l := range seg.layers {
+		if l.Name != name {
+			layers = append(layers, l)
+		}
+	}
+
+	seg.layers = layers
+}
+
+// Layers return the layers of the segmenter sorted by priority
+func (seg *Segmenter) Layers() []*Layer {
+	return seg.layers
+}
+
+// WithLayers return a segmenter copy with the layers added,
+// the dictionaries are shared, so it is cheap to create for each request:
+//
+//	s := seg.WithLayers(tenant, request)
+//	s.Cut(text)
+func (seg *Segmenter) WithLayers(layers ...*Layer) *Segmenter {
+	s := *seg
+	s.layers = make([]*Layer, len(seg.layers), len(seg.layers)+len(layers))
+	copy(s.layers, seg.layers)
+	s.AddLayer(layers...)
+	return &s
+}
+
+// maxTokenLen the maximum length of the dictionary and layers
+func (seg *Segmenter) maxTokenLen() int {
+	max := seg.Dict.maxTokenLen
+	for _, l := range seg.layers {
+		max = maxInt(max, l.Dict.maxTokenLen)
+	}
+
+	return max
+}
+
+// lookupTokens finds tokens in the dictionary and layers,
+// the higher priority token is used when the same word in several ones,
+// returns the tokens sorted by length like Dictionary.LookupTokens
+func (seg *Segmenter) lookupTokens(words []Text, tokens []*Token) (numOfTokens int) {
+	if len(seg.layers) == 0 {
+		return seg.Dict.LookupTokens(words, tokens)
+	}
+
+	for i := range words {
+		tokens[i] = nil
+	}
+
+	base := false
+	lookup := func(dict *Dictionary) {
+		id := 0
+		for i, word := range words {
+			var err error
+			id, err = dict.trie.Jump(word, id)
+			if err != nil {
+				break
+			}
+
+			value, err := dict.trie.Value(id)
+			if err == nil {
+				tokens[i] = &dict.Tokens[value]
+			}
+		}
+	}
+
+	// lookup from the lowest priority, the higher one overwrites it
+	for _, l := range seg.layers {
+		if !base && l.Priority >= 0 {
+			lookup(seg.Dict)
+			base = true
+		}
+		lookup(l.Dict)
+	}
+	if !base {
+		lookup(seg.Dict)
+	}
+
+	for i := range words {
+		if tokens[i] != nil {
+			tokens[numOfTokens] = tokens[i]
+			numOfTokens++
+		}
+	}
+
+	return
+}
+
+// find the word in the dictionary and layers,
+// returns the frequency scaled by the layer
+func (seg *Segmenter) find(word []byte) (float64, string, bool) {
+	freq, pos, ok := seg.Dict.Find(word)
+	if len(seg.layers) == 0 {
+		return freq, pos, ok
+	}
+
+	found := ok && freq > 0
+	for i := len(seg.layers) - 1; i >= 0; i-- {
+		l := seg.layers[i]
+		if found && l.Priority < 0 {
+			break
+		}
+
+		f, p, exist := l.Dict.Find(word)
+		if exist && f > 0 {
+			return f * l.Scale, p, true
+		}
+		ok = ok || exist
+	}
+
+	return freq, pos, ok
+}
+
+// value find the word in the dictionary and layers like find,
+// returns the dictionary having the word and the value of it
+func (seg *Segmenter) value(word []byte) (*Dictionary, int, int, error) {
+	val, id, err := seg.Dict.Value(word)
+	if len(seg.layers) == 0 {
+		return seg.Dict, val, id, err
+	}
+
+	found := err == nil && seg.Dict.IsLive(val) && seg.Dict.Tokens[val].freq > 0
+	for i := len(seg.layers) - 1; i >= 0; i-- {
+		l := seg.layers[i]
+		if found && l.Priority < 0 {
+			break
+		}
+
+		v, lid, lerr := l.Dict.Value(word)
+		if lerr == nil && l.Dict.IsLive(v) && l.Dict.Tokens[v].freq > 0 {
+			return l.Dict, v, lid, nil
+		}
+	}
+
+	return seg.Dict, val, id, err
+}
@@ -0,0 +1,69 @@
+package gse
+
+import (
+	"testing"
+
+	"github.com/vcaesar/tt"
+)
+
+func TestLayer(t *testing.T) {
+	var seg1 Segmenter
+	seg1.SkipLog = true
+	err := seg1.LoadDict("testdata/zh/test_dict.txt")
+	tt.Nil(t, err)
+
+	text := "上海中心大厦王八乌龟"
+	tt.Equal(t, "上海/ns 中心大厦/nr 王八乌龟/nr ", seg1.String(text))
+
+	domain := seg1.NewLayer("domain", 10)
+	err = domain.LoadDictStr("上海中心大厦 100 nt\n海中 2 n")
+	tt.Nil(t, err)
+	tt.Equal(t, 2, domain.Dict.NumTokens())
+
+	tenant := seg1.NewLayer("tenant", 20, 1000)
+	err = tenant.AddToken("中心大厦", 3, "nz")
+	tt.Nil(t, err)
+
+	request := seg1.NewLayer("request", -1)
+	err = request.AddToken("乌龟", 1000000, "n")
+	tt.Nil(t, err)
+	err = request.AddToken("王八", 1, "x")
+	tt.Nil(t, err)
+
+	s := seg1.WithLayers(tenant, request)
+	tt.Equal(t, 2, len(s.Layers()))
+	tt.Equal(t, "request", s.Layers()[0].Name)
+	tt.Equal(t, 0, len(seg1.Layers()))
+	tt.Equal(t, "上海/ns 中心大厦/nz 王八/nr 乌龟/n ", s.String(text))
+	tt.Equal(t, "上海/ns 中心大厦/nr 王八乌龟/nr ", seg1.String(text))
+
+	f, pos, ok := s.Find("中心大厦")
+	tt.Bool(t, ok)
+	tt.Equal(t, "nz", pos)
+	tt.Equal(t, 3000, f)
+
+	f, pos, _ = s.Find("王八")
+	tt.Equal(t, "nr", pos)
+	tt.Equal(t, 494, f)
+
+	val, _, err := s.Value("中心大厦")
+	tt.Nil(t, err)
+	tt.Equal(t, 0, val)
+	token, ok := s.FindToken("中心大厦")
+	tt.True(t, ok)
+	tt.Equal(t, "nz", token.Pos())
+	token, ok = s.FindToken("王八")
+	tt.True(t, ok)
+	tt.Equal(t, "nr", token.Pos())
+	_, ok = s.FindToken("不存在")
+	tt.False(t, ok)
+
+	seg1.AddLayer(domain)
+	tt.Equal(t, "上海中心大厦/nt 王八乌龟/nr ", seg1.String(text))
+	tt.Equal(t, "[上海中心大厦 王八乌龟]", seg1.Cut(text, false))
+	tt.Equal(t, "[上海 中心 大厦 中心大厦 上海中心大厦 王八 乌 龟 王八乌龟]", seg1.CutSearch(text))
+
+	seg1.RemoveLayer("domain")
+	tt.Equal(t, 0, len(seg1.Layers()))
+	tt.Equal(t, "上海/ns 中心大厦/nr 王八乌龟/nr ", seg1.String(text))
+}
@@ -18,13 +18,13 @@ import (
 	"bufio"
 	"fmt"
 	"io"
-	"log"
 	"math"
 	"os"
 	"path"
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 )
 
@@ -147,6 +147,12 @@ func (seg *Segmenter) LoadDictMap(dict []map[string]string) error {
 //
 // When a participle appears both in the user dictionary and
 // in the `common dictionary`, the `user dictionary` is given priority.
+//
+// The format of each file is selected by the prefix or extension,
+//
+//	such as: "jieba:user_dict.txt,domain.tsv,lexicon.jsonl"
+//
+// see DictFormatOf for details.
 func (seg *Segmenter) LoadDict(files ...string) error {
 	if !seg.Load {
 		seg.Dict = NewDict()
@@ -158,16 +164,15 @@ func (seg *Segmenter) LoadDict(files ...string) error {
 		dictDir  = path.Join(path.Dir(seg.GetCurrentFilePath()), "data")
 		dictPath string
 		// load     bool
+		start = time.Now()
 	)
 
 	if len(files) > 0 {
 		dictFiles := DictPaths(dictDir, files[0])
-		if !seg.SkipLog {
-			log.Println("Dict files path: ", dictFiles)
-		}
+		seg.Log().Info("gse dict files path", "files", dictFiles)
 
 		if len(dictFiles) == 0 {
-			log.Println("Warning: dict files is nil.")
+			seg.Log().Warn("gse dict files is nil")
 			// return errors.New("Dict files is nil.")
 		}
 
@@ -209,9 +214,8 @@ func (seg *Segmenter) LoadDict(files ...string) error {
 	// }
 
 	seg.CalcToken()
-	if !seg.SkipLog {
-		log.Println("Gse dictionary loaded finished.")
-	}
+	seg.Log().Info("gse dictionary loaded finished",
+		"tokens", seg.Dict.NumTokens(), "duration", time.Since(start))
 
 	return nil
 }
@@ -239,20 +243,59 @@ func (seg *Segmenter) GetIdfPath(files ...string) []string {
 }
 
 // Read read the dict file
+//
+// The format of the file is detected by DictFormatOf
 func (seg *Segmenter) Read(file string) error {
-	if !seg.SkipLog {
-		log.Printf("Load the gse dictionary: \"%s\" ", file)
-	}
+	format, file := DictFormatOf(file)
+	seg.Log().Info("load the gse dictionary", "file", file, "format", format)
+	start := time.Now()
 
 	dictFile, err := os.Open(file)
 	if err != nil {
-		log.Printf("Could not load dictionaries: \"%s\", %v \n", file, err)
+		seg.Log().Error("could not load dictionaries", "file", file, "error", err)
 		return err
 	}
 	defer dictFile.Close()
 
-	reader := bufio.NewReader(dictFile)
-	return seg.Reader(reader, file)
+	num := seg.Dict.NumTokens()
+	err = seg.ReadFormat(dictFile, format, file)
+
+	seg.Log().Debug("gse dictionary file loaded", "file", file,
+		"tokens", seg.Dict.NumTokens()-num, "duration", time.Since(start))
+	return err
+}
+
+// SaveDict save the live tokens of the dictionary to the file,
+// the format is detected by DictFormatOf like LoadDict,
+// the file is written to a temporary file and renamed at last
+func (seg *Segmenter) SaveDict(file string) error {
+	format, file := DictFormatOf(file)
+	start := time.Now()
+
+	tmp := file + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		seg.Log().Error("could not save the dictionary", "file", file, "error", err)
+		return err
+	}
+
+	err = seg.Dict.Export(f, format, seg.DictSep)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	if err == nil {
+		err = os.Rename(tmp, file)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		seg.Log().Error("could not save the dictionary", "file", file, "error", err)
+		return err
+	}
+
+	seg.Log().Info("gse dictionary saved", "file", file, "format", format,
+		"duration", time.Since(start))
+	return nil
 }
 
 // Size frequency is calculated based on the size of the text
@@ -353,13 +396,11 @@ func (seg *Segmenter) Reader(reader *bufio.Reader, files ...string) error {
 			}
 
 			if size > 0 {
-				if seg.MoreLog {
-					log.Printf("File '%v' line \"%v\" read error: %v, skip",
-						file, line, fsErr.Error())
-				}
+				seg.Log().Debug("dict line read error, skip",
+					"file", file, "line", line, "error", fsErr)
 			} else {
-				log.Printf("File '%v' line \"%v\" is empty, read error: %v, skip",
-					file, line, fsErr.Error())
+				seg.Log().Warn("dict line is empty, skip",
+					"file", file, "line", line, "error", fsErr)
 			}
 		}
 
@@ -14,6 +14,11 @@
 package gse
 
 import (
+	"bytes"
+	"io"
+	"sort"
+
+	"github.com/go-ego/gse/internal/fileio"
 	"github.com/vcaesar/cedar"
 )
 
@@ -141,3 +146,39 @@ func (dict *Dictionary) Value(word []byte) (val, id int, err error) {
 	val, err = dict.trie.Value(id)
 	return
 }
+
+// IsLive check the dictionary tokens[index] is not removed
+func (dict *Dictionary) IsLive(index int) bool {
+	if index < 0 || index >= len(dict.Tokens) {
+		return false
+	}
+
+	val, err := dict.trie.Get(textSliceToBytes(dict.Tokens[index].text))
+	return err == nil && val == index
+}
+
+// LiveTokens returns the tokens not removed from the dictionary,
+// sorted by the token text
+func (dict *Dictionary) LiveTokens() []*Token {
+	tokens := make([]*Token, 0, len(dict.Tokens))
+	for i := range dict.Tokens {
+		if dict.IsLive(i) {
+			tokens = append(tokens, &dict.Tokens[i])
+		}
+	}
+
+	sort.Slice(tokens, func(i, j int) bool {
+		return bytes.Compare(textSliceToBytes(tokens[i].text),
+			textSliceToBytes(tokens[j].text)) < 0
+	})
+
+	return tokens
+}
+
+// WriteTo write the live tokens to w in the gse dictionary format,
+// "text freq pos" for each line, return the number of bytes written
+func (dict *Dictionary) WriteTo(w io.Writer) (int64, error) {
+	cw := &fileio.CountWriter{W: w}
+	err := dict.Export(cw, FormatGse)
+	return cw.N, err
+}
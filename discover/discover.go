@@ -0,0 +1,326 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+/*
+Package discover is the unsupervised new word discovery from raw corpora,
+the candidates are ranked by the n-gram frequency, the pointwise mutual
+information (cohesion) and the left and right branching entropy (freedom).
+*/
+package discover
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-ego/gse"
+)
+
+// Options the new word discovery options
+type Options struct {
+	// MaxLen the maximum length of the word in units
+	// (a Han character or an alphanumeric word), default is 4
+	MaxLen int
+	// MinFreq the minimum frequency of the word in the corpus, default is 5
+	MinFreq int
+	// MinPMI the minimum pointwise mutual information, default is 1.0
+	MinPMI float64
+	// MinEntropy the minimum left and right branching entropy, default is 1.0
+	MinEntropy float64
+
+	// KeepKnown keep the words already in the dictionary
+	KeepKnown bool
+}
+
+// Word the new word candidate
+type Word struct {
+	Text string
+	// Freq the frequency in the corpus
+	Freq int
+	// PMI the minimum pointwise mutual information of the splits
+	PMI float64
+	// Left and Right the branching entropy of the neighbors
+	Left, Right float64
+	// Score log(Freq) * PMI * min(Left, Right)
+	Score float64
+
+	// SuggestFreq the suggested dictionary frequency by seg.SuggestFreq,
+	// not less than the Freq
+	SuggestFreq float64
+}
+
+type neighbors struct {
+	left, right map[string]int
+	// the sentence boundary, each one is treated as a different neighbor
+	leftEnd, rightEnd int
+}
+
+// Discoverer the new word discoverer, add the corpus by Add or AddReader
+// and get the ranked candidates by Words
+type Discoverer struct {
+	seg *gse.Segmenter
+	opt Options
+
+	total  int
+	counts map[string]int
+	around map[string]*neighbors
+}
+
+// New create a new Discoverer, the words already in
+// the seg dictionary are filtered out, the seg can be nil
+func New(seg *gse.Segmenter, opts ...Options) *Discoverer {
+	var opt Options
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	if opt.MaxLen <= 1 {
+		opt.MaxLen = 4
+	}
+	if opt.MinFreq <= 0 {
+		opt.MinFreq = 5
+	}
+	if opt.MinPMI == 0 {
+		opt.MinPMI = 1.0
+	}
+	if opt.MinEntropy == 0 {
+		opt.MinEntropy = 1.0
+	}
+
+	return &Discoverer{
+		seg:    seg,
+		opt:    opt,
+		counts: make(map[string]int),
+		around: make(map[string]*neighbors),
+	}
+}
+
+// sep the separator of the units in the n-gram key, so "ab"+"c" and
+// "a"+"bc" are different, the units have no space
+const sep = " "
+
+// chunks split the text by the punctuation and symbol,
+// the spaces between the Latin words are kept
+func chunks(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && !unicode.IsSpace(r)
+	})
+}
+
+func (d *Discoverer) units(chunk string) []string {
+	var words []gse.Text
+	if d.seg != nil {
+		words = d.seg.SplitTextToWords([]byte(chunk))
+	} else {
+		words = gse.SplitWords([]byte(chunk))
+	}
+
+	units := make([]string, 0, len(words))
+	for _, w := range words {
+		if u := strings.TrimSpace(string(w)); u != "" {
+			units = append(units, u)
+		}
+	}
+	return units
+}
+
+func isHan(r rune) bool {
+	return unicode.Is(unicode.Han, r)
+}
+
+// join join the units to the word text, drop the separator
+// only next to the Han characters
+func join(units []string) string {
+	var b strings.Builder
+	for i, u := range units {
+		if i > 0 {
+			last, _ := utf8.DecodeLastRuneInString(units[i-1])
+			first, _ := utf8.DecodeRuneInString(u)
+			if !isHan(last) && !isHan(first) {
+				b.WriteString(sep)
+			}
+		}
+		b.WriteString(u)
+	}
+	return b.String()
+}
+
+// Add add the text to the corpus statistics
+func (d *Discoverer) Add(text string) {
+	for _, chunk := range chunks(text) {
+		d.addUnits(d.units(chunk))
+	}
+}
+
+func (d *Discoverer) addUnits(units []string) {
+	d.total += len(units)
+
+	for i := range units {
+		gram := ""
+		for n := 1; n <= d.opt.MaxLen && i+n <= len(units); n++ {
+			if n > 1 {
+				gram += sep
+			}
+			gram += units[i+n-1]
+			d.counts[gram]++
+			if n < 2 {
+				continue
+			}
+
+			nb, ok := d.around[gram]
+			if !ok {
+				nb = &neighbors{left: make(map[string]int), right: make(map[string]int)}
+				d.around[gram] = nb
+			}
+
+			if i > 0 {
+				nb.left[units[i-1]]++
+			} else {
+				nb.leftEnd++
+			}
+
+			if i+n < len(units) {
+				nb.right[units[i+n]]++
+			} else {
+				nb.rightEnd++
+			}
+		}
+	}
+}
+
+// AddReader add the corpus from the reader line by line
+func (d *Discoverer) AddReader(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		d.Add(scanner.Text())
+	}
+
+	return scanner.Err()
+}
+
+// entropy the branching entropy of the neighbors
+func entropy(m map[string]int, end int) float64 {
+	n := end
+	for _, c := range m {
+		n += c
+	}
+	if n == 0 {
+		return 0
+	}
+
+	total := float64(n)
+	e := 0.0
+	for _, c := range m {
+		p := float64(c) / total
+		e -= p * math.Log(p)
+	}
+
+	// the boundaries are different from each other
+	e += float64(end) / total * math.Log(total)
+	return e
+}
+
+// pmi the minimum pointwise mutual information of the two parts splits
+func (d *Discoverer) pmi(units []string, count int) float64 {
+	total := float64(d.total)
+	pw := float64(count) / total
+
+	min := math.MaxFloat64
+	for i := 1; i < len(units); i++ {
+		a := d.counts[strings.Join(units[:i], sep)]
+		b := d.counts[strings.Join(units[i:], sep)]
+		v := math.Log(pw / (float64(a) / total * float64(b) / total))
+		if v < min {
+			min = v
+		}
+	}
+
+	return min
+}
+
+func isNum(units []string) bool {
+	for _, u := range units {
+		for _, r := range u {
+			if !unicode.IsNumber(r) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Words return the new word candidates sorted by score,
+// topK is the maximum number of the results, 0 is all
+func (d *Discoverer) Words(topK ...int) (words []Word) {
+	for gram, count := range d.counts {
+		if count < d.opt.MinFreq {
+			continue
+		}
+
+		nb, ok := d.around[gram]
+		if !ok {
+			continue
+		}
+
+		units := strings.Split(gram, sep)
+		if len(units) < 2 || isNum(units) {
+			continue
+		}
+
+		text := join(units)
+		w := Word{Text: text, Freq: count}
+		w.PMI = d.pmi(units, count)
+		if w.PMI < d.opt.MinPMI {
+			continue
+		}
+
+		w.Left = entropy(nb.left, nb.leftEnd)
+		w.Right = entropy(nb.right, nb.rightEnd)
+		if w.Left < d.opt.MinEntropy || w.Right < d.opt.MinEntropy {
+			continue
+		}
+
+		w.SuggestFreq = float64(count)
+		if d.seg != nil && d.seg.Dict != nil {
+			freq, _, ok := d.seg.Find(text)
+			if ok && freq > 0 && !d.opt.KeepKnown {
+				continue
+			}
+
+			w.SuggestFreq = math.Max(w.SuggestFreq, d.seg.SuggestFreq(text))
+		}
+
+		w.Score = math.Log(float64(count)) * w.PMI * math.Min(w.Left, w.Right)
+		words = append(words, w)
+	}
+
+	sort.Slice(words, func(i, j int) bool {
+		if words[i].Score == words[j].Score {
+			return words[i].Text < words[j].Text
+		}
+		return words[i].Score > words[j].Score
+	})
+
+	if len(topK) > 0 && topK[0] > 0 && len(words) > topK[0] {
+		words = words[:topK[0]]
+	}
+
+	return
+}
@@ -0,0 +1,81 @@
+package discover
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-ego/gse"
+	"github.com/vcaesar/tt"
+)
+
+var corpus = []string{
+	"今天真是蓝瘦香菇，我想哭",
+	"他说蓝瘦香菇了一整天",
+	"蓝瘦香菇是什么意思",
+	"听到消息后蓝瘦香菇",
+	"大家都在说蓝瘦香菇吗",
+	"考试没过真的蓝瘦香菇啊",
+	"你蓝瘦香菇我也蓝瘦香菇",
+	"上海是个好地方，我喜欢上海的夜景",
+	"上海的天气不错，在上海工作",
+}
+
+func TestDiscover(t *testing.T) {
+	d := New(nil)
+	for _, text := range corpus {
+		d.Add(text)
+	}
+
+	words := d.Words()
+	tt.Equal(t, 1, len(words))
+	tt.Equal(t, "蓝瘦香菇", words[0].Text)
+	tt.Equal(t, 8, words[0].Freq)
+	tt.Equal(t, 8, words[0].SuggestFreq)
+	tt.True(t, words[0].PMI > 2)
+	tt.True(t, words[0].Left > 1.5)
+	tt.True(t, words[0].Right > 1.5)
+
+	var seg gse.Segmenter
+	seg.SkipLog = true
+	err := seg.LoadDict("../testdata/zh/test_dict.txt")
+	tt.Nil(t, err)
+
+	d = New(&seg, Options{MinFreq: 3, MinEntropy: 0.5})
+	err = d.AddReader(strings.NewReader(strings.Join(corpus, "\n")))
+	tt.Nil(t, err)
+
+	words = d.Words(10)
+	tt.Equal(t, "蓝瘦香菇", words[0].Text)
+	tt.Equal(t, 8, words[0].SuggestFreq)
+	for _, w := range words {
+		tt.NotEqual(t, "上海", w.Text)
+	}
+
+	d = New(&seg, Options{MinFreq: 3, MinEntropy: 0.5, KeepKnown: true})
+	d.Add(strings.Join(corpus, "。"))
+	words = d.Words()
+	tt.Equal(t, "[蓝瘦香菇 上海]", []string{words[0].Text, words[1].Text})
+}
+
+func TestLatin(t *testing.T) {
+	d := New(nil, Options{MinFreq: 3, MinEntropy: 0.5})
+	for _, text := range []string{
+		"we use deep learning today", "deep learning is hot",
+		"i like deep learning", "learn deep learning now",
+		"so deep learning, ok", "the cat is here", "a cat runs",
+	} {
+		d.Add(text)
+	}
+
+	words := d.Words(1)
+	tt.Equal(t, "deep learning", words[0].Text)
+	tt.Equal(t, 5, words[0].Freq)
+
+	d = New(nil)
+	d.Add("ab c")
+	d.Add("a bc")
+	tt.Equal(t, 1, d.counts["ab c"])
+	tt.Equal(t, 1, d.counts["a bc"])
+	tt.Equal(t, "蓝瘦香菇", join([]string{"蓝", "瘦", "香", "菇"}))
+	tt.Equal(t, "iphone手机", join([]string{"iphone", "手", "机"}))
+}
@@ -0,0 +1,137 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package filter
+
+import "github.com/vcaesar/cedar"
+
+// automaton the Aho-Corasick automaton on the cedar double array trie,
+// the goto function is the trie jump of the byte
+type automaton struct {
+	trie *cedar.Cedar
+	// fail the failure links, link the nearest node with the output
+	// in the failure chain
+	fail, link map[int]int
+	// out the pattern id of the terminal node
+	out  map[int]int
+	lens []int
+}
+
+// hit the pattern matched ending at the byte end
+type hit struct {
+	id, end int
+}
+
+func (a *automaton) next(from int, c byte) (int, bool) {
+	to, err := a.trie.Jump([]byte{c}, from)
+	return to, err == nil
+}
+
+// newAutomaton build the automaton of the patterns, the pattern id is
+// the index, the patterns should not contain the zero byte
+func newAutomaton(patterns []string) *automaton {
+	a := &automaton{trie: cedar.New(),
+		fail: make(map[int]int), link: make(map[int]int), out: make(map[int]int)}
+
+	maxLen := 0
+	for id, p := range patterns {
+		a.trie.Insert([]byte(p), id)
+		a.lens = append(a.lens, len(p))
+		if len(p) > maxLen {
+			maxLen = len(p)
+		}
+	}
+
+	// the nodes by the depth, the node ids are stable after the inserts
+	type item struct {
+		node, parent int
+		c            byte
+	}
+	depths := make([][]item, maxLen+1)
+	seen := make(map[int]bool)
+	for _, p := range patterns {
+		parent := 0
+		for d := 1; d <= len(p); d++ {
+			node, _ := a.next(parent, p[d-1])
+			if !seen[node] {
+				seen[node] = true
+				depths[d] = append(depths[d], item{node, parent, p[d-1]})
+			}
+			parent = node
+		}
+	}
+
+	for d := 1; d <= maxLen; d++ {
+		for _, it := range depths[d] {
+			if v, err := a.trie.Value(it.node); err == nil {
+				a.out[it.node] = v
+			}
+
+			f := 0
+			if d > 1 {
+				f = a.fail[it.parent]
+				for {
+					if to, ok := a.next(f, it.c); ok {
+						f = to
+						break
+					}
+					if f == 0 {
+						break
+					}
+					f = a.fail[f]
+				}
+			}
+
+			a.fail[it.node] = f
+			if _, ok := a.out[f]; ok {
+				a.link[it.node] = f
+			} else {
+				a.link[it.node] = a.link[f]
+			}
+		}
+	}
+
+	return a
+}
+
+// scan scan the bytes in one pass, return the all hits,
+// the zero byte resets the automaton
+func (a *automaton) scan(b []byte) (hits []hit) {
+	state := 0
+	for i, c := range b {
+		if c == 0 {
+			state = 0
+			continue
+		}
+
+		for {
+			if to, ok := a.next(state, c); ok {
+				state = to
+				break
+			}
+			if state == 0 {
+				break
+			}
+			state = a.fail[state]
+		}
+
+		for n := state; n != 0; n = a.link[n] {
+			if id, ok := a.out[n]; ok {
+				hits = append(hits, hit{id: id, end: i})
+			}
+		}
+	}
+
+	return
+}
@@ -0,0 +1,533 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+/*
+Package filter is the sensitive words filter by the Aho-Corasick automaton
+on the cedar double array trie, scan the raw text in one pass, ignore the
+inserted symbols and spaces, and optionally match the Traditional variants
+and the pinyin:
+
+	f := filter.New(filter.Options{Variants: true})
+	f.Add("敏感词")
+	matches := f.Find("这是敏*感*詞")
+	text := f.Mask("这是敏*感*詞") // 这是*****
+*/
+package filter
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-ego/gse"
+)
+
+// DefaultVariants the default Traditional and Simplified variants pairs
+var DefaultVariants = `與与 專专 業业 東东 絲丝 兩两 嚴严 個个 豐丰 臨临 為为 麗丽
+舉举 義义 烏乌 樂乐 習习 鄉乡 書书 買买 亂乱 爭争 虧亏 雲云 亞亚 產产 親亲 億亿
+僅仅 從从 倉仓 儀仪 們们 價价 眾众 優优 會会 傘伞 偉伟 傳传 傷伤 倫伦 偽伪 體体
+餘余 來来 俠侠 側侧 偵侦 備备 債债 傾倾 僑侨 儲储 兒儿 內内 岡冈 冊册 寫写 軍军
+農农 馮冯 沖冲 決决 況况 凍冻 淨净 涼凉 減减 幾几 鳳凤 憑凭 凱凯 擊击 劃划 劉刘
+則则 剛刚 創创 刪删 別别 劑剂 劇剧 勸劝 辦办 務务 動动 勵励 勁劲 勞劳 勢势 區区
+醫医 華华 協协 單单 賣卖 衛卫 卻却 廠厂 廳厅 歷历 厲厉 壓压 厭厌 縣县 參参 雙双
+發发 變变 葉叶 號号 嘆叹 嚇吓 嗎吗 啟启 員员 問问 喚唤 喪丧 團团 園园 圍围 國国
+圖图 圓圆 聖圣 場场 壞坏 塊块 堅坚 壇坛 執执 報报 夠够 夢梦 夾夹 奪夺 奮奋 獎奖
+婦妇 媽妈 孫孙 學学 寧宁 寶宝 實实 審审 憲宪 對对 尋寻 導导 屆届 屬属 歲岁 島岛
+幣币 師师 帳帐 帶带 幫帮 廣广 莊庄 慶庆 庫库 應应 廢废 開开 張张 彈弹 強强 歸归
+當当 錄录 後后 徑径 復复 態态 戀恋 惡恶 愛爱 懷怀 憂忧 慮虑 戰战 戲戏 戶户 擴扩
+掃扫 揚扬 換换 擁拥 擇择 擔担 據据 擠挤 攝摄 擺摆 搶抢 護护 擬拟 攜携 敵敌 數数
+齊齐 斷断 時时 顯显 暫暂 條条 楊杨 極极 構构 槍枪 樣样 標标 機机 權权 歡欢 歐欧
+殺杀 毀毁 氣气 漢汉 湯汤 溝沟 滅灭 滿满 濟济 灣湾 災灾 無无 煙烟 熱热 燈灯 爺爷
+牆墙 獨独 獄狱 獲获 環环 現现 畫画 療疗 盡尽 監监 盤盘 著着 礦矿 碼码 確确 禮礼
+禍祸 離离 種种 稱称 積积 穩稳 窮穷 竊窃 競竞 筆笔 築筑 簡简 類类 糧粮 緊紧 紅红
+約约 級级 紀纪 純纯 紙纸 線线 組组 細细 終终 結结 給给 絕绝 統统 經经 綠绿 維维
+網网 編编 練练 總总 績绩 織织 繼继 續续 罰罚 羅罗 聞闻 聯联 聲声 聽听 職职 腦脑
+臉脸 興兴 舊旧 藝艺 節节 範范 藥药 蘇苏 蟲虫 術术 補补 裝装 製制 見见 規规 視视
+覺觉 觀观 計计 訂订 認认 討讨 讓让 訓训 議议 記记 講讲 許许 論论 設设 訪访 證证
+評评 識识 詞词 試试 詩诗 話话 該该 詳详 語语 誤误 說说 請请 讀读 課课 誰谁 調调
+談谈 謝谢 貝贝 負负 財财 責责 貨货 質质 販贩 貧贫 購购 貸贷 費费 資资 賊贼 賽赛
+贊赞 贈赠 趕赶 趙赵 躍跃 車车 軟软 輕轻 載载 輛辆 輸输 轉转 辭辞 邊边 達达 遷迁
+過过 運运 還还 這这 進进 遠远 違违 連连 遲迟 適适 選选 遺遗 鄧邓 鄭郑 醜丑 釋释
+裡里 針针 鈔钞 鋼钢 錢钱 錯错 鍵键 鎮镇 鏡镜 鐘钟 鐵铁 長长 門门 閃闪 閉闭 間间
+閱阅 關关 陽阳 陰阴 陣阵 階阶 際际 陸陆 隊队 隨随 險险 隱隐 雖虽 雞鸡 難难 電电
+霧雾 靜静 韓韩 頁页 頂顶 項项 順顺 須须 預预 領领 頭头 題题 額额 顏颜 願愿 風风
+飛飞 飯饭 飲饮 館馆 馬马 駕驾 驗验 驚惊 鬥斗 魚鱼 鮮鲜 鳥鸟 鹽盐 麥麦 黃黄 點点
+黨党 齒齿 龍龙 龜龟 賭赌 詐诈 騙骗 銷销 紮扎 恥耻 屍尸 暈晕 臟脏 闆板`
+
+// IsNoise the rune is ignored by the default, such as the punctuations,
+// the symbols, the spaces and the zero width characters
+func IsNoise(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r) ||
+		unicode.Is(unicode.Cf, r)
+}
+
+// Options the filter options
+type Options struct {
+	// Skip the runes ignored in the words and the text, default is IsNoise
+	Skip func(r rune) bool
+	// Variants match the Traditional variants by the DefaultVariants
+	// and the AddVariant
+	Variants bool
+	// Pinyin match the pinyin of the words, such as "mingan" and "敏gan"
+	// of the "敏感", the pinyin is loaded by the LoadPinyin
+	Pinyin bool
+}
+
+// Match the matched sensitive word
+type Match struct {
+	// Word the sensitive word, Text the matched text
+	Word, Text string
+	// Start and End the bytes offsets of the text
+	Start, End int
+	// Pinyin the text is matched by the pinyin
+	Pinyin bool
+}
+
+// Filter the sensitive words filter
+type Filter struct {
+	opt Options
+
+	words    []string
+	variants map[rune]rune
+	pinyin   map[rune][]string
+
+	mu    sync.Mutex
+	dirty bool
+	snap  *snapshot
+}
+
+// snapshot the immutable words, variants, pinyin and automata of the
+// build, it is read by the Find without the lock
+type snapshot struct {
+	opt      Options
+	words    []string
+	variants map[rune]rune
+	pinyin   map[rune][]string
+
+	direct, py *automaton
+	// dWords and pyWords the word indexes of the patterns
+	dWords, pyWords []int
+}
+
+// New create a new Filter
+func New(opts ...Options) *Filter {
+	var opt Options
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if opt.Skip == nil {
+		opt.Skip = IsNoise
+	}
+
+	f := &Filter{opt: opt, dirty: true,
+		variants: make(map[rune]rune), pinyin: make(map[rune][]string)}
+	if opt.Variants {
+		for _, p := range strings.Fields(DefaultVariants) {
+			rs := []rune(p)
+			if len(rs) == 2 {
+				f.variants[rs[0]] = rs[1]
+			}
+		}
+	}
+
+	return f
+}
+
+// Len return the number of the words
+func (f *Filter) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.words)
+}
+
+// Add add the sensitive words
+func (f *Filter) Add(words ...string) {
+	f.mu.Lock()
+	for _, w := range words {
+		if w = strings.TrimSpace(w); w != "" {
+			f.words = append(f.words, w)
+		}
+	}
+	f.dirty = true
+	f.mu.Unlock()
+}
+
+// AddStop add the stop words of the segmenter as the sensitive words
+func (f *Filter) AddStop(seg *gse.Segmenter) {
+	words := make([]string, 0, len(seg.StopWordMap))
+	for w := range seg.StopWordMap {
+		words = append(words, w)
+	}
+	sort.Strings(words)
+	f.Add(words...)
+}
+
+// ReadDict read the sensitive words of the lines, use the first field
+// of the line, the "#" lines are comments
+func (f *Filter) ReadDict(r io.Reader) error {
+	var words []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		words = append(words, strings.Fields(text)[0])
+	}
+
+	f.Add(words...)
+	return scanner.Err()
+}
+
+// LoadDictStr load the sensitive words from the string
+func (f *Filter) LoadDictStr(str string) error {
+	return f.ReadDict(strings.NewReader(str))
+}
+
+// LoadDict load the sensitive words files
+func (f *Filter) LoadDict(files ...string) error {
+	for _, file := range files {
+		fr, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+
+		err = f.ReadDict(fr)
+		fr.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddVariant add the Traditional variant of the Simplified rune
+func (f *Filter) AddVariant(trad, simp rune) {
+	f.mu.Lock()
+	f.variants[trad] = simp
+	f.dirty = true
+	f.mu.Unlock()
+}
+
+// ReadPinyin read the pinyin of the "字 zi4" lines, the heteronym has
+// the more readings, such as "行 xing2 hang2", the tones are removed
+func (f *Filter) ReadPinyin(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		r, _ := utf8.DecodeRuneInString(fields[0])
+		for _, py := range fields[1:] {
+			py = strings.ToLower(strings.TrimRight(py, "012345"))
+			py = strings.ReplaceAll(py, "ü", "v")
+			if py != "" {
+				f.pinyin[r] = append(f.pinyin[r], py)
+			}
+		}
+	}
+
+	f.dirty = true
+	return scanner.Err()
+}
+
+// LoadPinyinStr load the pinyin from the string
+func (f *Filter) LoadPinyinStr(str string) error {
+	return f.ReadPinyin(strings.NewReader(str))
+}
+
+// LoadPinyin load the pinyin files
+func (f *Filter) LoadPinyin(files ...string) error {
+	for _, file := range files {
+		fr, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+
+		err = f.ReadPinyin(fr)
+		fr.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// norm normalize the rune, return false if it is skipped
+func (s *snapshot) norm(r rune) (rune, bool) {
+	if s.opt.Skip(r) {
+		return r, false
+	}
+
+	// the full width forms
+	if r >= '！' && r <= '～' {
+		r -= '！' - '!'
+	}
+	r = unicode.ToLower(r)
+	if v, ok := s.variants[r]; ok && s.opt.Variants {
+		r = v
+	}
+	return r, true
+}
+
+func isLetter(r rune) bool {
+	return r >= 'a' && r <= 'z'
+}
+
+// pinyins return the pinyin patterns of the normalized word,
+// at most 32 combinations of the heteronyms
+func (s *snapshot) pinyins(word []rune) []string {
+	pys := []string{""}
+	han := false
+	for _, r := range word {
+		readings := s.pinyin[r]
+		if isLetter(r) {
+			readings = []string{string(r)}
+		} else if len(readings) == 0 {
+			return nil
+		} else {
+			han = true
+		}
+
+		var next []string
+		for _, p := range pys {
+			for _, rd := range readings {
+				if len(next) < 32 {
+					next = append(next, p+rd)
+				}
+			}
+		}
+		pys = next
+	}
+
+	if !han {
+		return nil
+	}
+	return pys
+}
+
+// build build the snapshot of the words with the automata,
+// it should be called with the lock
+func (f *Filter) build() {
+	s := &snapshot{opt: f.opt,
+		words:    append([]string(nil), f.words...),
+		variants: make(map[rune]rune, len(f.variants)),
+		pinyin:   make(map[rune][]string, len(f.pinyin)),
+	}
+	for k, v := range f.variants {
+		s.variants[k] = v
+	}
+	for k, v := range f.pinyin {
+		s.pinyin[k] = append([]string(nil), v...)
+	}
+
+	var (
+		patterns, pyPatterns []string
+		seen                 = make(map[string]bool)
+	)
+	for i, w := range s.words {
+		var word []rune
+		for _, r := range w {
+			if n, ok := s.norm(r); ok {
+				word = append(word, n)
+			}
+		}
+		if len(word) == 0 {
+			continue
+		}
+
+		if p := string(word); !seen[p] {
+			seen[p] = true
+			patterns = append(patterns, p)
+			s.dWords = append(s.dWords, i)
+		}
+
+		if !s.opt.Pinyin {
+			continue
+		}
+		for _, p := range s.pinyins(word) {
+			if !seen["\x00"+p] {
+				seen["\x00"+p] = true
+				pyPatterns = append(pyPatterns, p)
+				s.pyWords = append(s.pyWords, i)
+			}
+		}
+	}
+
+	s.direct = newAutomaton(patterns)
+	s.py = newAutomaton(pyPatterns)
+	f.snap, f.dirty = s, false
+}
+
+// stream the normalized bytes of the text, with the offsets of the
+// source runes of the bytes
+type stream struct {
+	b          []byte
+	start, end []int
+	// letter the byte is of the Latin letter in the text
+	letter []bool
+}
+
+func (s *stream) add(b []byte, start, end int, letter bool) {
+	for _, c := range b {
+		s.b = append(s.b, c)
+		s.start = append(s.start, start)
+		s.end = append(s.end, end)
+		s.letter = append(s.letter, letter)
+	}
+}
+
+// streams return the normalized and the pinyin streams of the text,
+// the skipped spaces between the Latin letters are not bridged
+func (s *snapshot) streams(text string) (d, py stream) {
+	var (
+		buf   [utf8.UTFMax]byte
+		space = -1
+	)
+	for i, r := range text {
+		end := i + utf8.RuneLen(r)
+		n, ok := s.norm(r)
+		if !ok {
+			if unicode.IsSpace(r) && len(d.letter) > 0 && d.letter[len(d.letter)-1] {
+				space = i
+			}
+			continue
+		}
+
+		if space >= 0 && isLetter(n) {
+			d.add([]byte{0}, space, space+1, false)
+		}
+		space = -1
+		d.add(buf[:utf8.EncodeRune(buf[:], n)], i, end, isLetter(n))
+		if !s.opt.Pinyin {
+			continue
+		}
+
+		switch {
+		case isLetter(n):
+			py.add([]byte{byte(n)}, i, end, true)
+		case len(s.pinyin[n]) > 0:
+			py.add([]byte(s.pinyin[n][0]), i, end, false)
+		default:
+			py.add([]byte{0}, i, end, false)
+		}
+	}
+
+	return
+}
+
+// Find find the sensitive words of the text,
+// the leftmost longest matches without the overlapping
+func (f *Filter) Find(text string) []Match {
+	f.mu.Lock()
+	if f.dirty {
+		f.build()
+	}
+	snap := f.snap
+	f.mu.Unlock()
+
+	direct, py := snap.direct, snap.py
+	d, ps := snap.streams(text)
+	var all []Match
+	for _, h := range direct.scan(d.b) {
+		s := h.end - direct.lens[h.id] + 1
+		// the Latin edges of the word should not be a part of the Latin word
+		if s > 0 && d.letter[s] && d.letter[s-1] ||
+			h.end+1 < len(d.b) && d.letter[h.end] && d.letter[h.end+1] {
+			continue
+		}
+		all = append(all, Match{Word: snap.words[snap.dWords[h.id]],
+			Start: d.start[s], End: d.end[h.end]})
+	}
+
+	for _, h := range py.scan(ps.b) {
+		s := h.end - py.lens[h.id] + 1
+		// the pinyin should be aligned to the runes and not a part of
+		// the Latin word
+		if s > 0 && (ps.start[s-1] == ps.start[s] || ps.letter[s-1] && ps.letter[s]) {
+			continue
+		}
+		e := h.end
+		if e+1 < len(ps.b) && (ps.start[e+1] == ps.start[e] || ps.letter[e+1] && ps.letter[e]) {
+			continue
+		}
+
+		all = append(all, Match{Word: snap.words[snap.pyWords[h.id]],
+			Start: ps.start[s], End: ps.end[e], Pinyin: true})
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].Start != all[j].Start {
+			return all[i].Start < all[j].Start
+		}
+		if all[i].End != all[j].End {
+			return all[i].End > all[j].End
+		}
+		return !all[i].Pinyin && all[j].Pinyin
+	})
+
+	var matches []Match
+	last := 0
+	for _, m := range all {
+		if m.Start >= last {
+			m.Text = text[m.Start:m.End]
+			matches = append(matches, m)
+			last = m.End
+		}
+	}
+	return matches
+}
+
+// Contains the text contains the sensitive words
+func (f *Filter) Contains(text string) bool {
+	return len(f.Find(text)) > 0
+}
+
+// Replace replace the matched text by the function
+func (f *Filter) Replace(text string, fn func(m Match) string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range f.Find(text) {
+		b.WriteString(text[last:m.Start])
+		b.WriteString(fn(m))
+		last = m.End
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// Mask replace the every rune of the matched text with the mask,
+// default is '*'
+func (f *Filter) Mask(text string, mask ...rune) string {
+	r := '*'
+	if len(mask) > 0 {
+		r = mask[0]
+	}
+
+	return f.Replace(text, func(m Match) string {
+		return strings.Repeat(string(r), utf8.RuneCountInString(m.Text))
+	})
+}
@@ -0,0 +1,125 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-ego/gse"
+	"github.com/vcaesar/tt"
+)
+
+func TestAutomaton(t *testing.T) {
+	a := newAutomaton([]string{"he", "she", "his", "hers"})
+	var got []string
+	for _, h := range a.scan([]byte("ushers")) {
+		got = append(got, []string{"he", "she", "his", "hers"}[h.id])
+	}
+	tt.Equal(t, "[she he hers]", got)
+}
+
+func TestFind(t *testing.T) {
+	f := New()
+	f.Add("敏感词", "敏感", "感词", "赌博")
+	tt.Equal(t, 4, f.Len())
+
+	text := "这是敏*感 词，不是赌​博"
+	m := f.Find(text)
+	tt.Equal(t, 2, len(m))
+	tt.Equal(t, "敏感词", m[0].Word)
+	tt.Equal(t, "敏*感 词", m[0].Text)
+	tt.Equal(t, m[0].Text, text[m[0].Start:m[0].End])
+	tt.Equal(t, "赌博", m[1].Word)
+
+	tt.Equal(t, "这是*****，不是***", f.Mask(text))
+	tt.Equal(t, "[敏感词]", f.Replace("敏感词", func(m Match) string {
+		return "[" + m.Word + "]"
+	}))
+	tt.False(t, f.Contains("正常的内容"))
+
+	f.Add("ＢＡＤ")
+	tt.Equal(t, "a ***** word", f.Mask("a B-a-D word"))
+}
+
+func TestLatinBoundary(t *testing.T) {
+	f := New()
+	f.Add("ass", "sb", "坏a")
+	tt.Equal(t, "class", f.Mask("class"))
+	tt.Equal(t, "I was sad", f.Mask("I was sad"))
+	tt.Equal(t, "usb", f.Mask("usb"))
+	tt.Equal(t, "you ***! **", f.Mask("you ass! SB"))
+	tt.Equal(t, "****", f.Mask("a.ss"))
+	tt.Equal(t, "**好", f.Mask("坏a好"))
+	tt.Equal(t, "坏ab", f.Mask("坏ab"))
+}
+
+func TestVariants(t *testing.T) {
+	f := New(Options{Variants: true})
+	f.LoadDictStr("# words\n赌博 1\n诈骗")
+	tt.Equal(t, "[賭博 詐騙]", words(f.Find("賭博和詐騙")))
+
+	f.AddVariant('財', '财')
+	f.Add("发财")
+	tt.Equal(t, "[發財]", words(f.Find("發財")))
+
+	tt.Equal(t, 0, len(New().Find("賭博")))
+}
+
+func words(ms []Match) []string {
+	var s []string
+	for _, m := range ms {
+		s = append(s, m.Text)
+	}
+	return s
+}
+
+func TestPinyin(t *testing.T) {
+	f := New(Options{Pinyin: true})
+	f.LoadPinyinStr("敏 min3\n感 gan3\n民 min2\n干 gan4 gan1")
+	f.Add("敏感")
+
+	m := f.Find("这个 min gan 话题和敏gan内容，民干")
+	tt.Equal(t, "[min gan 敏gan 民干]", words(m))
+	tt.True(t, m[0].Pinyin)
+	tt.Equal(t, "敏感", m[1].Word)
+
+	tt.Equal(t, 0, len(f.Find("remingany")))
+	tt.Equal(t, 1, len(f.Find("敏感")))
+	tt.False(t, f.Find("敏感")[0].Pinyin)
+}
+
+func TestStop(t *testing.T) {
+	var seg gse.Segmenter
+	seg.LoadStopStr("坏词\n脏话")
+
+	f := New()
+	f.AddStop(&seg)
+	tt.Equal(t, "说***和**", f.Mask("说坏_词和脏话"))
+
+	var b strings.Builder
+	for i := 0; i < 2000; i++ {
+		b.WriteString("词")
+		f.Add("坏词" + strings.Repeat("x", i%7))
+	}
+	tt.Equal(t, 2, len(f.Find("坏词"+b.String()+"脏话")))
+}
+
+func TestConcurrent(t *testing.T) {
+	f := New(Options{Variants: true, Pinyin: true})
+	f.Add("赌博")
+
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 200; i++ {
+			f.AddVariant(rune(0x4e00+i), '赌')
+			f.LoadPinyinStr("赌 du3")
+			f.Add("诈骗")
+		}
+		done <- true
+	}()
+
+	for i := 0; i < 200; i++ {
+		f.Find("賭博和诈骗")
+	}
+	<-done
+	tt.Equal(t, 2, len(f.Find("賭博和诈骗")))
+}
@@ -0,0 +1,253 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+/*
+Package fingerprint is the SimHash and MinHash document fingerprints
+of the gse tokens for the near-duplicate detection,
+with the LSH (locality sensitive hashing) index for the candidate lookup.
+*/
+package fingerprint
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"strings"
+	"unicode"
+
+	"github.com/go-ego/gse"
+	"github.com/go-ego/gse/hmm/idf"
+)
+
+// Options the fingerprint options
+type Options struct {
+	// Idf weight the SimHash tokens by the IDF,
+	// the words not in it use the median IDF
+	Idf *idf.Idf
+	// Stop remove the stop words of the segmenter, see seg.LoadStop
+	Stop bool
+	// Shingle the words number of the MinHash shingles, default is 1
+	Shingle int
+	// NumHash the length of the MinHash signature, default is 128
+	NumHash int
+}
+
+// Hasher make the fingerprints of the text
+type Hasher struct {
+	seg   *gse.Segmenter
+	opt   Options
+	seeds []uint64
+}
+
+// New create a new Hasher with the segmenter
+func New(seg *gse.Segmenter, opts ...Options) *Hasher {
+	var opt Options
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if opt.Shingle <= 0 {
+		opt.Shingle = 1
+	}
+	if opt.NumHash <= 0 {
+		opt.NumHash = 128
+	}
+
+	h := &Hasher{seg: seg, opt: opt, seeds: make([]uint64, opt.NumHash)}
+	seed := uint64(0)
+	for i := range h.seeds {
+		seed = splitmix64(seed)
+		h.seeds[i] = seed
+	}
+
+	return h
+}
+
+// splitmix64 the 64 bits mixer, used as the MinHash permutations
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// Hash the 64 bits FNV-1a hash of the token
+func Hash(token string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(token))
+	return h.Sum64()
+}
+
+func isWord(w string) bool {
+	return strings.IndexFunc(w, func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsNumber(r)
+	}) >= 0
+}
+
+// Tokens cut the text to the lower case words,
+// the punctuations, spaces and stop words (if Stop) are removed
+func (h *Hasher) Tokens(text string) []string {
+	var tokens []string
+	for _, w := range h.seg.Cut(text, true) {
+		w = strings.ToLower(strings.TrimSpace(w))
+		if !isWord(w) || (h.opt.Stop && h.seg.IsStop(w)) {
+			continue
+		}
+
+		tokens = append(tokens, w)
+	}
+
+	return tokens
+}
+
+// Weights return the weights of the tokens,
+// the term frequency or the TF-IDF if the Idf is set
+func (h *Hasher) Weights(tokens []string) map[string]float64 {
+	weights := make(map[string]float64)
+	for _, w := range tokens {
+		weights[w]++
+	}
+
+	if h.opt.Idf != nil {
+		for w, tf := range weights {
+			v, _, ok := h.opt.Idf.Freq(w)
+			if !ok {
+				v = h.opt.Idf.Median()
+			}
+			weights[w] = tf * v
+		}
+	}
+
+	return weights
+}
+
+// SimHashWeights the 64 bits SimHash of the weighted features
+func SimHashWeights(weights map[string]float64) uint64 {
+	var v [64]float64
+	for w, weight := range weights {
+		hash := Hash(w)
+		for i := 0; i < 64; i++ {
+			if hash&(1<<uint(i)) != 0 {
+				v[i] += weight
+			} else {
+				v[i] -= weight
+			}
+		}
+	}
+
+	var fp uint64
+	for i := 0; i < 64; i++ {
+		if v[i] > 0 {
+			fp |= 1 << uint(i)
+		}
+	}
+
+	return fp
+}
+
+// SimHash the 64 bits SimHash of the text
+func (h *Hasher) SimHash(text string) uint64 {
+	return SimHashWeights(h.Weights(h.Tokens(text)))
+}
+
+// Shingles return the word n-grams of the tokens by the Shingle option
+func (h *Hasher) Shingles(tokens []string) []string {
+	n := h.opt.Shingle
+	if len(tokens) < n {
+		if len(tokens) == 0 {
+			return nil
+		}
+		return []string{strings.Join(tokens, " ")}
+	}
+
+	shingles := make([]string, 0, len(tokens)-n+1)
+	for i := 0; i+n <= len(tokens); i++ {
+		shingles = append(shingles, strings.Join(tokens[i:i+n], " "))
+	}
+
+	return shingles
+}
+
+// Signature the MinHash signature
+type Signature []uint64
+
+// MinHashShingles the MinHash signature of the shingles
+func (h *Hasher) MinHashShingles(shingles []string) Signature {
+	sig := make(Signature, len(h.seeds))
+	for i := range sig {
+		sig[i] = ^uint64(0)
+	}
+
+	for _, s := range shingles {
+		hash := Hash(s)
+		for i, seed := range h.seeds {
+			if v := splitmix64(hash ^ seed); v < sig[i] {
+				sig[i] = v
+			}
+		}
+	}
+
+	return sig
+}
+
+// MinHash the MinHash signature of the text
+func (h *Hasher) MinHash(text string) Signature {
+	return h.MinHashShingles(h.Shingles(h.Tokens(text)))
+}
+
+// Hamming the hamming distance of the two SimHash
+func Hamming(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Jaccard the estimated Jaccard similarity of the two MinHash signatures
+func Jaccard(a, b Signature) float64 {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	if n == 0 {
+		return 0
+	}
+
+	same := 0
+	for i := 0; i < n; i++ {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+
+	return float64(same) / float64(n)
+}
+
+// JaccardSet the exact Jaccard similarity of the two token sets
+func JaccardSet(a, b []string) float64 {
+	set := make(map[string]int)
+	for _, s := range a {
+		set[s] |= 1
+	}
+	for _, s := range b {
+		set[s] |= 2
+	}
+	if len(set) == 0 {
+		return 0
+	}
+
+	both := 0
+	for _, v := range set {
+		if v == 3 {
+			both++
+		}
+	}
+
+	return float64(both) / float64(len(set))
+}
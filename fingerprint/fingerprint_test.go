@@ -0,0 +1,70 @@
+package fingerprint
+
+import (
+	"testing"
+
+	"github.com/go-ego/gse"
+	"github.com/go-ego/gse/hmm/idf"
+	"github.com/vcaesar/tt"
+)
+
+var (
+	doc1 = "The quick brown fox jumps over the lazy dog near the river bank today"
+	doc2 = "The quick brown fox jumps over the lazy dog near the river bank"
+	doc3 = "Stock markets rallied as investors cheered the central bank decision"
+)
+
+func newHasher(opts ...Options) *Hasher {
+	var seg gse.Segmenter
+	seg.SkipLog = true
+	seg.LoadDictStr("河岸 100 n")
+	seg.LoadStopArr([]string{"the", "over", "as"})
+	return New(&seg, opts...)
+}
+
+func TestSimHash(t *testing.T) {
+	h := newHasher(Options{Stop: true})
+	tt.Equal(t, "[quick brown fox jumps lazy dog near river bank today]", h.Tokens(doc1))
+
+	a, b, c := h.SimHash(doc1), h.SimHash(doc2), h.SimHash(doc3)
+	tt.True(t, Hamming(a, b) < Hamming(a, c))
+	tt.Equal(t, 0, Hamming(a, a))
+	tt.Equal(t, 64, Hamming(0, ^uint64(0)))
+
+	i := idf.NewIdf()
+	i.LoadDictStr("today 0.1\nstock 9")
+	h1 := newHasher(Options{Idf: i, Stop: true})
+	tt.True(t, Hamming(h1.SimHash(doc1), h1.SimHash(doc2)) <= Hamming(a, b))
+
+	index := NewSimIndex(8)
+	index.Add("1", a)
+	index.Add("3", c)
+	tt.Equal(t, "[1]", index.Query(a, 0))
+	tt.Equal(t, "[1]", index.Query(b, Hamming(a, b)))
+}
+
+func TestMinHash(t *testing.T) {
+	h := newHasher(Options{Shingle: 2, NumHash: 200})
+	tt.Equal(t, "[a b b c]", h.Shingles([]string{"a", "b", "c"}))
+	tt.Equal(t, "[a]", h.Shingles([]string{"a"}))
+
+	s1, s2, s3 := h.MinHash(doc1), h.MinHash(doc2), h.MinHash(doc3)
+	tt.Equal(t, 200, len(s1))
+
+	exact := JaccardSet(h.Shingles(h.Tokens(doc1)), h.Shingles(h.Tokens(doc2)))
+	tt.Equal(t, 12.0/13, exact)
+	tt.True(t, Jaccard(s1, s2) > 0.8)
+	tt.True(t, Jaccard(s1, s3) < 0.1)
+	tt.Equal(t, 1, Jaccard(s1, s1))
+
+	lsh := NewLSH(40, 5)
+	lsh.Add("1", s1)
+	lsh.Add("3", s3)
+	tt.Equal(t, "[1]", lsh.Query(s2))
+	tt.Equal(t, "[3]", lsh.Query(s3))
+
+	lsh = NewLSH(0, -1)
+	lsh.Add("1", s1)
+	tt.Equal(t, "[1]", lsh.Query(s1))
+	tt.Equal(t, "[]", lsh.Query(s3))
+}
@@ -0,0 +1,145 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package fingerprint
+
+import (
+	"sort"
+)
+
+// LSH the MinHash index by banding, the signature is split to
+// the bands of rows, the documents with any same band are the candidates.
+//
+// The probability of the candidate is 1 - (1 - s^rows)^bands
+// for the Jaccard similarity s.
+type LSH struct {
+	bands, rows int
+	buckets     []map[uint64][]string
+}
+
+// NewLSH create a new LSH index, the bands * rows
+// should not be more than the signature length,
+// the default is 16 bands of 8 rows for the 128 signature
+func NewLSH(bands, rows int) *LSH {
+	if bands <= 0 {
+		bands = 16
+	}
+	if rows <= 0 {
+		rows = 8
+	}
+
+	l := &LSH{bands: bands, rows: rows, buckets: make([]map[uint64][]string, bands)}
+	for i := range l.buckets {
+		l.buckets[i] = make(map[uint64][]string)
+	}
+
+	return l
+}
+
+// band the hash of the band i of the signature
+func (l *LSH) band(sig Signature, i int) uint64 {
+	hash := uint64(i)
+	for _, v := range sig[i*l.rows : (i+1)*l.rows] {
+		hash = splitmix64(hash ^ v)
+	}
+	return hash
+}
+
+// Add add the document signature to the index
+func (l *LSH) Add(id string, sig Signature) {
+	for i := 0; i < l.bands && (i+1)*l.rows <= len(sig); i++ {
+		key := l.band(sig, i)
+		l.buckets[i][key] = append(l.buckets[i][key], id)
+	}
+}
+
+// Query return the candidate document ids sorted
+func (l *LSH) Query(sig Signature) []string {
+	seen := make(map[string]bool)
+	for i := 0; i < l.bands && (i+1)*l.rows <= len(sig); i++ {
+		for _, id := range l.buckets[i][l.band(sig, i)] {
+			seen[id] = true
+		}
+	}
+
+	return sortedKeys(seen)
+}
+
+func sortedKeys(m map[string]bool) []string {
+	ids := make([]string, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// SimIndex the SimHash index by the blocks of bits,
+// by the pigeonhole principle, the SimHash within the distance k
+// has at least one same block when the blocks are more than k
+type SimIndex struct {
+	blocks  int
+	hashes  map[string]uint64
+	buckets []map[uint64][]string
+}
+
+// NewSimIndex create a new SimHash index with the blocks (1 to 64)
+func NewSimIndex(blocks int) *SimIndex {
+	if blocks <= 0 || blocks > 64 {
+		blocks = 4
+	}
+
+	s := &SimIndex{
+		blocks:  blocks,
+		hashes:  make(map[string]uint64),
+		buckets: make([]map[uint64][]string, blocks),
+	}
+	for i := range s.buckets {
+		s.buckets[i] = make(map[uint64][]string)
+	}
+
+	return s
+}
+
+// block the bits of the block i
+func (s *SimIndex) block(hash uint64, i int) uint64 {
+	start := 64 * i / s.blocks
+	end := 64 * (i + 1) / s.blocks
+	mask := ^uint64(0) >> uint(64-(end-start))
+	return (hash >> uint(start)) & mask
+}
+
+// Add add the document SimHash to the index
+func (s *SimIndex) Add(id string, hash uint64) {
+	s.hashes[id] = hash
+	for i := 0; i < s.blocks; i++ {
+		key := s.block(hash, i)
+		s.buckets[i][key] = append(s.buckets[i][key], id)
+	}
+}
+
+// Query return the document ids within the hamming distance sorted,
+// the distance should be less than the blocks
+func (s *SimIndex) Query(hash uint64, distance int) []string {
+	seen := make(map[string]bool)
+	for i := 0; i < s.blocks; i++ {
+		for _, id := range s.buckets[i][s.block(hash, i)] {
+			if !seen[id] && Hamming(hash, s.hashes[id]) <= distance {
+				seen[id] = true
+			}
+		}
+	}
+
+	return sortedKeys(seen)
+}
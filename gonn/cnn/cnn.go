@@ -1 +1,352 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+/*
+Package cnn is the pure Go CNN text classifier of the characters
+or the gse words, with the embedding, convolution, max-pooling
+and softmax layers:
+
+	model := cnn.Train(samples, cnn.TrainOptions{Seg: &seg, Word: true})
+	label := model.Classify("text")
+
+The model file is little endian, a header line, a JSON line and the float32
+parameters in the order below, the matrix is row major:
+
+	gse-cnn 1
+	{"embed": E, "widths": [...], "filters": F, "word": false, "vocab": [...], "labels": [...]}
+	embedding   [V][E]      (V = len(vocab) + 1, 0 is the unknown)
+	conv W      [F][w*E]    (for each width w)
+	conv B      [F]
+	output W    [L][F*len(widths)]  (L = len(labels))
+	output B    [L]
+
+The segmenter is not saved, set the model.Seg after loading the word model.
+*/
 package cnn
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/go-ego/gse"
+	"github.com/go-ego/gse/internal/fileio"
+)
+
+const magic = "gse-cnn 1"
+
+// Conv the convolution layer of the width
+type Conv struct {
+	Width int
+	W     []float32
+	B     []float32
+}
+
+// Model the CNN text classifier
+type Model struct {
+	Embed, Filters int
+	Widths         []int
+	// Word use the segmenter words as the input instead of the characters
+	Word   bool
+	Labels []string
+	// Seg the segmenter of the word model
+	Seg *gse.Segmenter
+
+	vocab  map[string]int
+	tokens []string
+
+	Emb   []float32
+	Convs []*Conv
+	Out   []float32
+	OutB  []float32
+}
+
+// Result the classification result
+type Result struct {
+	Label string
+	Prob  float64
+}
+
+type header struct {
+	Embed   int      `json:"embed"`
+	Widths  []int    `json:"widths"`
+	Filters int      `json:"filters"`
+	Word    bool     `json:"word"`
+	Vocab   []string `json:"vocab"`
+	Labels  []string `json:"labels"`
+}
+
+// maxParams the max parameters of the model file
+const maxParams = 1 << 30
+
+// valid check the sizes of the header before creating the model
+func (h *header) valid() bool {
+	if h.Embed <= 0 || h.Filters <= 0 || len(h.Widths) == 0 || len(h.Labels) == 0 ||
+		h.Embed > maxParams || h.Filters > maxParams {
+		return false
+	}
+
+	n := (len(h.Vocab) + 1) * h.Embed
+	for _, w := range h.Widths {
+		if w <= 0 || w > maxParams/h.Embed/h.Filters {
+			return false
+		}
+		n += h.Filters*w*h.Embed + h.Filters
+		if n > maxParams {
+			return false
+		}
+	}
+	return n <= maxParams && h.Filters*len(h.Widths) <= maxParams/len(h.Labels)
+}
+
+// NewModel create a new model with the zero parameters
+func NewModel(vocab, labels []string, embed, filters int, widths []int) *Model {
+	m := &Model{Embed: embed, Filters: filters, Widths: widths, Labels: labels}
+	m.setVocab(vocab)
+
+	m.Emb = make([]float32, (len(vocab)+1)*embed)
+	for _, w := range widths {
+		m.Convs = append(m.Convs, &Conv{Width: w,
+			W: make([]float32, filters*w*embed),
+			B: make([]float32, filters),
+		})
+	}
+	m.Out = make([]float32, len(labels)*m.features())
+	m.OutB = make([]float32, len(labels))
+
+	return m
+}
+
+func (m *Model) setVocab(vocab []string) {
+	m.tokens = vocab
+	m.vocab = make(map[string]int, len(vocab))
+	for i, t := range vocab {
+		m.vocab[t] = i + 1
+	}
+}
+
+// features return the size of the pooled features
+func (m *Model) features() int {
+	return m.Filters * len(m.Widths)
+}
+
+// params return all the parameters in the file order
+func (m *Model) params() [][]float32 {
+	ps := [][]float32{m.Emb}
+	for _, c := range m.Convs {
+		ps = append(ps, c.W, c.B)
+	}
+	return append(ps, m.Out, m.OutB)
+}
+
+// Vocab return the vocabulary of the model
+func (m *Model) Vocab() []string {
+	return m.tokens
+}
+
+// Tokens return the input tokens of the text,
+// the segmenter words of the word model or the characters
+func (m *Model) Tokens(text string) (tokens []string) {
+	if m.Word && m.Seg != nil {
+		for _, w := range m.Seg.Cut(text, true) {
+			if w = strings.TrimSpace(w); w != "" {
+				tokens = append(tokens, w)
+			}
+		}
+		return
+	}
+
+	for _, r := range text {
+		if !unicode.IsSpace(r) {
+			tokens = append(tokens, string(unicode.ToLower(r)))
+		}
+	}
+	return
+}
+
+// ids return the vocab index of the tokens,
+// padded by the unknown to the max width
+func (m *Model) ids(tokens []string) []int {
+	ids := make([]int, len(tokens))
+	for i, t := range tokens {
+		ids[i] = m.vocab[t]
+	}
+
+	for _, w := range m.Widths {
+		for len(ids) < w {
+			ids = append(ids, 0)
+		}
+	}
+	return ids
+}
+
+// pass the forward pass of the sample
+type pass struct {
+	ids   []int
+	feat  []float32
+	arg   []int
+	probs []float64
+}
+
+// forward run the convolution, max-pooling and softmax layers,
+// arg is the pooled position of each feature, -1 if it is not activated
+func (m *Model) forward(ids []int) *pass {
+	e := m.Embed
+	p := &pass{ids: ids,
+		feat: make([]float32, m.features()),
+		arg:  make([]int, m.features()),
+	}
+
+	for ci, c := range m.Convs {
+		size := c.Width * e
+		for f := 0; f < m.Filters; f++ {
+			k := ci*m.Filters + f
+			p.arg[k] = -1
+			w := c.W[f*size : (f+1)*size]
+
+			for pos := 0; pos+c.Width <= len(ids); pos++ {
+				s := c.B[f]
+				for j := 0; j < c.Width; j++ {
+					id := ids[pos+j]
+					s += dot(w[j*e:(j+1)*e], m.Emb[id*e:(id+1)*e])
+				}
+
+				// the ReLU and max-pooling
+				if s > p.feat[k] {
+					p.feat[k], p.arg[k] = s, pos
+				}
+			}
+		}
+	}
+
+	n := m.features()
+	p.probs = make([]float64, len(m.Labels))
+	for y := range m.Labels {
+		p.probs[y] = float64(m.OutB[y] + dot(m.Out[y*n:(y+1)*n], p.feat))
+	}
+	softmax(p.probs)
+
+	return p
+}
+
+func dot(a, b []float32) (s float32) {
+	for i := range a {
+		s += a[i] * b[i]
+	}
+	return
+}
+
+func softmax(v []float64) {
+	max := math.Inf(-1)
+	for _, x := range v {
+		max = math.Max(max, x)
+	}
+
+	sum := 0.0
+	for i, x := range v {
+		v[i] = math.Exp(x - max)
+		sum += v[i]
+	}
+	for i := range v {
+		v[i] /= sum
+	}
+}
+
+// Predict return the labels with the probability of the text,
+// sorted by the probability
+func (m *Model) Predict(text string) []Result {
+	p := m.forward(m.ids(m.Tokens(text)))
+	results := make([]Result, len(m.Labels))
+	for y, label := range m.Labels {
+		results[y] = Result{Label: label, Prob: p.probs[y]}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Prob > results[j].Prob
+	})
+	return results
+}
+
+// Classify return the most probable label of the text
+func (m *Model) Classify(text string) string {
+	if len(m.Labels) == 0 {
+		return ""
+	}
+	return m.Predict(text)[0].Label
+}
+
+// Accuracy return the accuracy of the model on the samples
+func (m *Model) Accuracy(samples []Sample) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	right := 0
+	for _, s := range samples {
+		if m.Classify(s.Text) == s.Label {
+			right++
+		}
+	}
+	return float64(right) / float64(len(samples))
+}
+
+// Read read the model from the reader
+func Read(r io.Reader) (*Model, error) {
+	var h header
+	line, br, err := fileio.ReadHeader(r, magic, &h)
+	if err == fileio.ErrMagic {
+		return nil, errors.New("cnn: not a gse cnn model")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !h.valid() {
+		return nil, fmt.Errorf("cnn: invalid model header %q", line)
+	}
+
+	m := NewModel(h.Vocab, h.Labels, h.Embed, h.Filters, h.Widths)
+	m.Word = h.Word
+	if err := fileio.ReadParams(br, m.params()); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Load load the model from the file
+func Load(file string) (*Model, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+// WriteTo write the model to w
+func (m *Model) WriteTo(w io.Writer) (int64, error) {
+	return fileio.WriteModel(w, magic, header{Embed: m.Embed, Widths: m.Widths,
+		Filters: m.Filters, Word: m.Word, Vocab: m.tokens, Labels: m.Labels}, m.params())
+}
+
+// Save write the model to the file
+func (m *Model) Save(file string) error {
+	return fileio.Save(file, m)
+}
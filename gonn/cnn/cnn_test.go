@@ -0,0 +1,83 @@
+package cnn
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-ego/gse"
+	"github.com/vcaesar/tt"
+)
+
+var samples = []Sample{
+	{"足球比赛今晚开始", "sport"},
+	{"篮球比赛结果出炉", "sport"},
+	{"球队赢得冠军", "sport"},
+	{"足球队员转会", "sport"},
+	{"股票市场大涨", "finance"},
+	{"银行利率下调", "finance"},
+	{"股市投资者关注利率", "finance"},
+	{"银行股票下跌", "finance"},
+}
+
+func TestTrain(t *testing.T) {
+	m := Train(samples, TrainOptions{Embed: 8, Filters: 8, Epochs: 30, Seed: 1})
+	tt.Equal(t, "[finance sport]", m.Labels)
+	tt.Equal(t, 1, m.Accuracy(samples))
+	tt.Equal(t, "sport", m.Classify("篮球冠军"))
+	tt.Equal(t, "finance", m.Classify("股票利率"))
+
+	r := m.Predict("篮球冠军")
+	tt.Equal(t, 2, len(r))
+	tt.True(t, r[0].Prob > 0.5)
+	tt.True(t, r[0].Prob >= r[1].Prob)
+
+	m = Train(samples, TrainOptions{Embed: 8, Filters: 8, Epochs: 20,
+		Adam: true, Seed: 1})
+	tt.Equal(t, 1, m.Accuracy(samples))
+	tt.Equal(t, "sport", m.Classify("足球冠军"))
+}
+
+func TestWord(t *testing.T) {
+	var seg gse.Segmenter
+	seg.SkipLog = true
+	seg.LoadDictStr(`足球 100 n
+篮球 100 n
+比赛 100 n
+股票 100 n
+银行 100 n
+利率 100 n`)
+
+	m := Train(samples, TrainOptions{Embed: 8, Filters: 8, Widths: []int{1, 2},
+		Word: true, Seg: &seg, Epochs: 30, Seed: 1})
+	tt.Equal(t, "[足球 比赛 今晚 开始]", m.Tokens("足球比赛 今晚开始"))
+	tt.Equal(t, "sport", m.Classify("足球比赛"))
+	tt.Equal(t, "finance", m.Classify("银行利率"))
+}
+
+func TestReadWrite(t *testing.T) {
+	m := Train(samples, TrainOptions{Embed: 4, Filters: 4, Epochs: 5, Seed: 1})
+
+	var buf bytes.Buffer
+	_, err := m.WriteTo(&buf)
+	tt.Nil(t, err)
+
+	m1, err := Read(&buf)
+	tt.Nil(t, err)
+	tt.Equal(t, m.Vocab(), m1.Vocab())
+	tt.Equal(t, m.Widths, m1.Widths)
+	tt.Equal(t, m.Predict("足球"), m1.Predict("足球"))
+
+	_, err = Read(bytes.NewBufferString("model\n"))
+	tt.NotNil(t, err)
+
+	for _, h := range []string{
+		`{"embed": 2, "widths": [0], "filters": 1, "labels": ["a"]}`,
+		`{"embed": 2, "widths": [-1], "filters": 1, "labels": ["a"]}`,
+		`{"embed": -2, "widths": [1], "filters": 1, "labels": ["a"]}`,
+		`{"embed": 2, "widths": [1], "filters": 0, "labels": ["a"]}`,
+		`{"embed": 99999999, "widths": [99999999], "filters": 9, "labels": ["a"]}`,
+	} {
+		_, err = Read(bytes.NewBufferString(magic + "\n" + h + "\n"))
+		tt.NotNil(t, err)
+	}
+}
@@ -0,0 +1,315 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cnn
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/go-ego/gse"
+)
+
+// Sample the training sample, the text with the label
+type Sample struct {
+	Text  string
+	Label string
+}
+
+// TrainOptions the training options
+type TrainOptions struct {
+	// Embed the embedding size, default is 32
+	Embed int
+	// Filters the filters number of each width, default is 32
+	Filters int
+	// Widths the convolution widths, default is [2, 3, 4]
+	Widths []int
+	// Word use the segmenter words as the input instead of the characters
+	Word bool
+	// Seg the segmenter of the word model
+	Seg *gse.Segmenter
+	// MinFreq the min frequency of the vocabulary tokens, default is 1
+	MinFreq int
+
+	// Epochs the training epochs, default is 10
+	Epochs int
+	// Adam use the Adam optimizer instead of the SGD
+	Adam bool
+	// LR the learning rate, default is 0.05 of the SGD and 0.01 of the Adam
+	LR float32
+	// Clip clip the gradients to [-Clip, Clip], default is 5
+	Clip float32
+	// Seed the random seed of the initialization and shuffle
+	Seed int64
+	// Logger log the loss of each epoch
+	Logger gse.Logger
+}
+
+func (opt *TrainOptions) init() {
+	if opt.Embed <= 0 {
+		opt.Embed = 32
+	}
+	if opt.Filters <= 0 {
+		opt.Filters = 32
+	}
+	if len(opt.Widths) == 0 {
+		opt.Widths = []int{2, 3, 4}
+	}
+	if opt.MinFreq <= 0 {
+		opt.MinFreq = 1
+	}
+	if opt.Epochs <= 0 {
+		opt.Epochs = 10
+	}
+	if opt.LR <= 0 {
+		opt.LR = 0.05
+		if opt.Adam {
+			opt.LR = 0.01
+		}
+	}
+	if opt.Clip <= 0 {
+		opt.Clip = 5
+	}
+}
+
+// Train train a new model by the samples
+func Train(samples []Sample, opts ...TrainOptions) *Model {
+	var opt TrainOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	opt.init()
+
+	tokenizer := &Model{Word: opt.Word, Seg: opt.Seg}
+	freqs := make(map[string]int)
+	labelSet := make(map[string]bool)
+	for _, s := range samples {
+		for _, t := range tokenizer.Tokens(s.Text) {
+			freqs[t]++
+		}
+		labelSet[s.Label] = true
+	}
+
+	var vocab []string
+	for t, freq := range freqs {
+		if freq >= opt.MinFreq {
+			vocab = append(vocab, t)
+		}
+	}
+	sort.Strings(vocab)
+
+	var labels []string
+	for l := range labelSet {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+
+	m := NewModel(vocab, labels, opt.Embed, opt.Filters, opt.Widths)
+	m.Word, m.Seg = opt.Word, opt.Seg
+
+	rnd := rand.New(rand.NewSource(opt.Seed))
+	uniform := func(p []float32, scale float64) {
+		for i := range p {
+			p[i] = float32((rnd.Float64()*2 - 1) * scale)
+		}
+	}
+
+	uniform(m.Emb, 0.1)
+	for _, c := range m.Convs {
+		uniform(c.W, 1/math.Sqrt(float64(c.Width*opt.Embed)))
+	}
+	uniform(m.Out, 1/math.Sqrt(float64(m.features())))
+
+	m.Train(samples, opt)
+	return m
+}
+
+// backward add the gradients of the pass to g, return the loss
+func (m *Model) backward(p *pass, gold int, g *Model) float64 {
+	e, n := m.Embed, m.features()
+	dfeat := make([]float32, n)
+	for y, prob := range p.probs {
+		d := float32(prob)
+		if y == gold {
+			d--
+		}
+		if d == 0 {
+			continue
+		}
+
+		g.OutB[y] += d
+		row, grow := m.Out[y*n:(y+1)*n], g.Out[y*n:(y+1)*n]
+		for k := range row {
+			grow[k] += d * p.feat[k]
+			dfeat[k] += d * row[k]
+		}
+	}
+
+	for ci, c := range m.Convs {
+		size := c.Width * e
+		gc := g.Convs[ci]
+		for f := 0; f < m.Filters; f++ {
+			k := ci*m.Filters + f
+			pos, d := p.arg[k], dfeat[k]
+			if pos < 0 || d == 0 {
+				continue
+			}
+
+			gc.B[f] += d
+			w, gw := c.W[f*size:(f+1)*size], gc.W[f*size:(f+1)*size]
+			for j := 0; j < c.Width; j++ {
+				id := p.ids[pos+j]
+				emb, gemb := m.Emb[id*e:(id+1)*e], g.Emb[id*e:(id+1)*e]
+				for i := 0; i < e; i++ {
+					gw[j*e+i] += d * emb[i]
+					gemb[i] += d * w[j*e+i]
+				}
+			}
+		}
+	}
+
+	return -math.Log(math.Max(p.probs[gold], 1e-12))
+}
+
+func clip(d, c float32) float32 {
+	if d > c {
+		return c
+	}
+	if d < -c {
+		return -c
+	}
+	return d
+}
+
+// optimizer update the parameters by the gradients and reset the gradients
+type optimizer interface {
+	update(params, grads [][]float32)
+}
+
+type sgd struct {
+	lr, clip float32
+}
+
+func (o *sgd) update(params, grads [][]float32) {
+	for i, g := range grads {
+		p := params[i]
+		for j, d := range g {
+			if d != 0 {
+				p[j] -= o.lr * clip(d, o.clip)
+				g[j] = 0
+			}
+		}
+	}
+}
+
+// adam the Adam optimizer, only the parameters with
+// the nonzero gradients are updated, such as the used embeddings
+type adam struct {
+	lr, clip, beta1, beta2, eps float64
+	t                           int
+	m, v                        [][]float32
+}
+
+func newAdam(params [][]float32, lr, clip float32) *adam {
+	o := &adam{lr: float64(lr), clip: float64(clip),
+		beta1: 0.9, beta2: 0.999, eps: 1e-8}
+	for _, p := range params {
+		o.m = append(o.m, make([]float32, len(p)))
+		o.v = append(o.v, make([]float32, len(p)))
+	}
+	return o
+}
+
+func (o *adam) update(params, grads [][]float32) {
+	o.t++
+	c1 := 1 - math.Pow(o.beta1, float64(o.t))
+	c2 := 1 - math.Pow(o.beta2, float64(o.t))
+
+	for i, g := range grads {
+		p, m, v := params[i], o.m[i], o.v[i]
+		for j, d := range g {
+			if d == 0 {
+				continue
+			}
+
+			x := float64(clip(d, float32(o.clip)))
+			mj := o.beta1*float64(m[j]) + (1-o.beta1)*x
+			vj := o.beta2*float64(v[j]) + (1-o.beta2)*x*x
+			m[j], v[j] = float32(mj), float32(vj)
+
+			p[j] -= float32(o.lr * (mj / c1) / (math.Sqrt(vj/c2) + o.eps))
+			g[j] = 0
+		}
+	}
+}
+
+// Train continue training the model by the samples,
+// the labels not in the model are ignored,
+// return the average loss of the last epoch
+func (m *Model) Train(samples []Sample, opts ...TrainOptions) float64 {
+	var opt TrainOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	opt.init()
+
+	labelIndex := make(map[string]int, len(m.Labels))
+	for i, l := range m.Labels {
+		labelIndex[l] = i
+	}
+
+	type item struct {
+		ids  []int
+		gold int
+	}
+	var items []item
+	for _, s := range samples {
+		if y, ok := labelIndex[s.Label]; ok {
+			items = append(items, item{ids: m.ids(m.Tokens(s.Text)), gold: y})
+		}
+	}
+
+	g := NewModel(m.tokens, m.Labels, m.Embed, m.Filters, m.Widths)
+	params, grads := m.params(), g.params()
+
+	var opti optimizer = &sgd{lr: opt.LR, clip: opt.Clip}
+	if opt.Adam {
+		opti = newAdam(params, opt.LR, opt.Clip)
+	}
+
+	rnd := rand.New(rand.NewSource(opt.Seed))
+	loss := 0.0
+	for epoch := 0; epoch < opt.Epochs; epoch++ {
+		start := time.Now()
+		rnd.Shuffle(len(items), func(i, j int) { items[i], items[j] = items[j], items[i] })
+
+		loss = 0.0
+		for _, it := range items {
+			loss += m.backward(m.forward(it.ids), it.gold, g)
+			opti.update(params, grads)
+		}
+		if len(items) > 0 {
+			loss /= float64(len(items))
+		}
+
+		if opt.Logger != nil {
+			opt.Logger.Info("cnn train epoch", "epoch", epoch+1,
+				"loss", loss, "duration", time.Since(start))
+		}
+	}
+
+	return loss
+}
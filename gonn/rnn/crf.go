@@ -0,0 +1,157 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package rnn
+
+import "math"
+
+// viterbi return the best tag path of the emissions by the CRF
+func (m *Model) viterbi(es [][]float32) []int {
+	n, t := len(es), len(m.Tags)
+	if n == 0 {
+		return nil
+	}
+
+	score := make([]float32, t)
+	for y := 0; y < t; y++ {
+		score[y] = m.Start[y] + es[0][y]
+	}
+
+	back := make([][]int, n)
+	next := make([]float32, t)
+	for k := 1; k < n; k++ {
+		back[k] = make([]int, t)
+		for y := 0; y < t; y++ {
+			best, from := float32(math.Inf(-1)), 0
+			for y0 := 0; y0 < t; y0++ {
+				if s := score[y0] + m.Trans[y0*t+y]; s > best {
+					best, from = s, y0
+				}
+			}
+			next[y] = best + es[k][y]
+			back[k][y] = from
+		}
+		score, next = next, score
+	}
+
+	for y := 0; y < t; y++ {
+		score[y] += m.End[y]
+	}
+
+	path := make([]int, n)
+	path[n-1] = argmax(score)
+	for k := n - 1; k > 0; k-- {
+		path[k-1] = back[k][path[k]]
+	}
+
+	return path
+}
+
+func logSumExp(v []float64) float64 {
+	max := math.Inf(-1)
+	for _, x := range v {
+		max = math.Max(max, x)
+	}
+	if math.IsInf(max, -1) {
+		return max
+	}
+
+	sum := 0.0
+	for _, x := range v {
+		sum += math.Exp(x - max)
+	}
+	return max + math.Log(sum)
+}
+
+// crfGrad return the negative log likelihood of the gold tags,
+// add the gradients of the transitions and return the emission gradients
+func (m *Model) crfGrad(es [][]float32, gold []int, g *Model) (float64, [][]float32) {
+	n, t := len(es), len(m.Tags)
+	trans := func(a, b int) float64 { return float64(m.Trans[a*t+b]) }
+
+	alpha := make([][]float64, n)
+	beta := make([][]float64, n)
+	buf := make([]float64, t)
+	for k := range alpha {
+		alpha[k] = make([]float64, t)
+		beta[k] = make([]float64, t)
+	}
+
+	for y := 0; y < t; y++ {
+		alpha[0][y] = float64(m.Start[y] + es[0][y])
+		beta[n-1][y] = float64(m.End[y])
+	}
+	for k := 1; k < n; k++ {
+		for y := 0; y < t; y++ {
+			for y0 := 0; y0 < t; y0++ {
+				buf[y0] = alpha[k-1][y0] + trans(y0, y)
+			}
+			alpha[k][y] = logSumExp(buf) + float64(es[k][y])
+		}
+	}
+	for k := n - 2; k >= 0; k-- {
+		for y := 0; y < t; y++ {
+			for y1 := 0; y1 < t; y1++ {
+				buf[y1] = trans(y, y1) + float64(es[k+1][y1]) + beta[k+1][y1]
+			}
+			beta[k][y] = logSumExp(buf)
+		}
+	}
+
+	for y := 0; y < t; y++ {
+		buf[y] = alpha[n-1][y] + float64(m.End[y])
+	}
+	logZ := logSumExp(buf)
+
+	gold0 := float64(m.Start[gold[0]] + m.End[gold[n-1]])
+	for k := 0; k < n; k++ {
+		gold0 += float64(es[k][gold[k]])
+		if k > 0 {
+			gold0 += trans(gold[k-1], gold[k])
+		}
+	}
+
+	des := make([][]float32, n)
+	for k := 0; k < n; k++ {
+		des[k] = make([]float32, t)
+		for y := 0; y < t; y++ {
+			des[k][y] = float32(math.Exp(alpha[k][y] + beta[k][y] - logZ))
+		}
+		des[k][gold[k]]--
+
+		if k == 0 {
+			for y := 0; y < t; y++ {
+				g.Start[y] += des[0][y]
+			}
+		}
+		if k == n-1 {
+			for y := 0; y < t; y++ {
+				g.End[y] += des[k][y]
+			}
+		}
+
+		if k > 0 {
+			for y0 := 0; y0 < t; y0++ {
+				for y := 0; y < t; y++ {
+					p := alpha[k-1][y0] + trans(y0, y) + float64(es[k][y]) +
+						beta[k][y] - logZ
+					g.Trans[y0*t+y] += float32(math.Exp(p))
+				}
+			}
+			g.Trans[gold[k-1]*t+gold[k]]--
+		}
+	}
+
+	return logZ - gold0, des
+}
@@ -0,0 +1,129 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package rnn
+
+import "math"
+
+// LSTM the long short-term memory layer,
+// the gates order is input, forget, cell and output
+type LSTM struct {
+	In, Hidden int
+
+	Wx []float32
+	Wh []float32
+	B  []float32
+}
+
+// NewLSTM create a new LSTM with the zero parameters
+func NewLSTM(in, hidden int) *LSTM {
+	return &LSTM{
+		In: in, Hidden: hidden,
+		Wx: make([]float32, 4*hidden*in),
+		Wh: make([]float32, 4*hidden*hidden),
+		B:  make([]float32, 4*hidden),
+	}
+}
+
+func dot(a, b []float32) (s float32) {
+	for i := range a {
+		s += a[i] * b[i]
+	}
+	return
+}
+
+func sigmoid(x float32) float32 {
+	return float32(1 / (1 + math.Exp(-float64(x))))
+}
+
+func tanh(x float32) float32 {
+	return float32(math.Tanh(float64(x)))
+}
+
+func argmax(v []float32) int {
+	k := 0
+	for i := range v {
+		if v[i] > v[k] {
+			k = i
+		}
+	}
+	return k
+}
+
+// gates calculate the activated gates of the step into z
+func (l *LSTM) gates(z, x, h []float32) {
+	n := l.Hidden
+	for j := range z {
+		z[j] = l.B[j] + dot(l.Wx[j*l.In:(j+1)*l.In], x) +
+			dot(l.Wh[j*n:(j+1)*n], h)
+	}
+
+	for j := 0; j < n; j++ {
+		z[j] = sigmoid(z[j])
+		z[n+j] = sigmoid(z[n+j])
+		z[2*n+j] = tanh(z[2*n+j])
+		z[3*n+j] = sigmoid(z[3*n+j])
+	}
+}
+
+// step update the cell and hidden state by the activated gates
+func (l *LSTM) step(z, c, h []float32) {
+	n := l.Hidden
+	for j := 0; j < n; j++ {
+		c[j] = z[n+j]*c[j] + z[j]*z[2*n+j]
+		h[j] = z[3*n+j] * tanh(c[j])
+	}
+}
+
+// Run run the batch of the input sequences, return the hidden states,
+// the reverse is the backward direction, the states are in the input order
+func (l *LSTM) Run(xs [][][]float32, reverse bool) [][][]float32 {
+	maxLen := 0
+	hs := make([][][]float32, len(xs))
+	cs := make([][]float32, len(xs))
+	prev := make([][]float32, len(xs))
+	for b := range xs {
+		hs[b] = make([][]float32, len(xs[b]))
+		cs[b] = make([]float32, l.Hidden)
+		prev[b] = make([]float32, l.Hidden)
+		if len(xs[b]) > maxLen {
+			maxLen = len(xs[b])
+		}
+	}
+
+	z := make([]float32, 4*l.Hidden)
+	for t := 0; t < maxLen; t++ {
+		for b := range xs {
+			n := len(xs[b])
+			if t >= n {
+				continue
+			}
+
+			k := t
+			if reverse {
+				k = n - 1 - t
+			}
+
+			l.gates(z, xs[b][k], prev[b])
+			h := make([]float32, l.Hidden)
+			copy(h, prev[b])
+			l.step(z, cs[b], h)
+
+			hs[b][k] = h
+			prev[b] = h
+		}
+	}
+
+	return hs
+}
@@ -1 +1,319 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+/*
+Package rnn is the pure Go BiLSTM-CRF character sequence labelling,
+for the word segmentation (the "B", "M", "E", "S" tags) and the POS tagging
+(the "B-n", "E-n" and so on tags), it can be used as the segmenter OOV cut:
+
+	model, err := rnn.Load("seg.model")
+	seg.OOVCut = model.Cut
+
+The model file is little endian, a header line, a JSON line and the float32
+parameters in the order below, the matrix is row major:
+
+	gse-bilstm-crf 1
+	{"embed": E, "hidden": H, "crf": true, "vocab": "...", "tags": [...]}
+	embedding      [V][E]   (V = runes of the vocab + 1, 0 is the unknown)
+	forward  Wx    [4H][E]  (the gates order: input, forget, cell, output)
+	forward  Wh    [4H][H]
+	forward  B     [4H]
+	backward Wx, Wh, B
+	output   W     [T][2H]  (T = len(tags), the input is [forward; backward])
+	output   B     [T]
+	transitions    [T][T]   (only if crf, from the row tag to the column tag)
+	start          [T]
+	end            [T]
+*/
 package rnn
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/go-ego/gse"
+	"github.com/go-ego/gse/internal/fileio"
+)
+
+const magic = "gse-bilstm-crf 1"
+
+// Model the BiLSTM-CRF model
+type Model struct {
+	Embed, Hidden int
+	CRF           bool
+	Tags          []string
+
+	vocab map[rune]int
+	runes []rune
+
+	Emb    []float32
+	Fw, Bw *LSTM
+	Out    []float32
+	OutB   []float32
+
+	Trans      []float32
+	Start, End []float32
+}
+
+type header struct {
+	Embed  int      `json:"embed"`
+	Hidden int      `json:"hidden"`
+	CRF    bool     `json:"crf"`
+	Vocab  string   `json:"vocab"`
+	Tags   []string `json:"tags"`
+}
+
+// maxParams the max parameters of the model file
+const maxParams = 1 << 30
+
+// valid check the sizes of the header before creating the model
+func (h *header) valid() bool {
+	e, hd, t := h.Embed, h.Hidden, len(h.Tags)
+	if e <= 0 || hd <= 0 || t == 0 || e > maxParams || hd > maxParams || t > maxParams {
+		return false
+	}
+
+	v := len([]rune(h.Vocab)) + 1
+	if v > maxParams/e || 4*hd > maxParams/e || 4*hd > maxParams/hd ||
+		t > maxParams/(2*hd) || t > maxParams/t {
+		return false
+	}
+
+	// each product is not greater than maxParams, the sum can not overflow
+	n := v*e + 2*(4*hd*e+4*hd*hd+4*hd) + t*2*hd + t
+	if h.CRF {
+		n += t*t + 2*t
+	}
+	return n <= maxParams
+}
+
+// NewModel create a new model with the zero parameters
+func NewModel(vocab []rune, tags []string, embed, hidden int, crf bool) *Model {
+	m := &Model{Embed: embed, Hidden: hidden, CRF: crf, Tags: tags}
+	m.setVocab(vocab)
+
+	t := len(tags)
+	m.Emb = make([]float32, (len(vocab)+1)*embed)
+	m.Fw = NewLSTM(embed, hidden)
+	m.Bw = NewLSTM(embed, hidden)
+	m.Out = make([]float32, t*2*hidden)
+	m.OutB = make([]float32, t)
+	if crf {
+		m.Trans = make([]float32, t*t)
+		m.Start = make([]float32, t)
+		m.End = make([]float32, t)
+	}
+
+	return m
+}
+
+func (m *Model) setVocab(vocab []rune) {
+	m.runes = vocab
+	m.vocab = make(map[rune]int, len(vocab))
+	for i, r := range vocab {
+		m.vocab[r] = i + 1
+	}
+}
+
+// params return all the parameters in the file order
+func (m *Model) params() [][]float32 {
+	ps := [][]float32{m.Emb, m.Fw.Wx, m.Fw.Wh, m.Fw.B,
+		m.Bw.Wx, m.Bw.Wh, m.Bw.B, m.Out, m.OutB}
+	if m.CRF {
+		ps = append(ps, m.Trans, m.Start, m.End)
+	}
+	return ps
+}
+
+// Read read the model from the reader
+func Read(r io.Reader) (*Model, error) {
+	var h header
+	line, br, err := fileio.ReadHeader(r, magic, &h)
+	if err == fileio.ErrMagic {
+		return nil, errors.New("rnn: not a gse bilstm-crf model")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !h.valid() {
+		return nil, fmt.Errorf("rnn: invalid model header %q", line)
+	}
+
+	m := NewModel([]rune(h.Vocab), h.Tags, h.Embed, h.Hidden, h.CRF)
+	if err := fileio.ReadParams(br, m.params()); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Load load the model from the file
+func Load(file string) (*Model, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+// WriteTo write the model to w
+func (m *Model) WriteTo(w io.Writer) (int64, error) {
+	return fileio.WriteModel(w, magic, header{Embed: m.Embed, Hidden: m.Hidden,
+		CRF: m.CRF, Vocab: string(m.runes), Tags: m.Tags}, m.params())
+}
+
+// Save write the model to the file
+func (m *Model) Save(file string) error {
+	return fileio.Save(file, m)
+}
+
+// ids return the vocab index of the runes
+func (m *Model) ids(runes []rune) []int {
+	ids := make([]int, len(runes))
+	for i, r := range runes {
+		ids[i] = m.vocab[r]
+	}
+	return ids
+}
+
+// emissions return the tag scores of each rune
+func (m *Model) emissions(hf, hb [][]float32) [][]float32 {
+	t, h := len(m.Tags), m.Hidden
+	es := make([][]float32, len(hf))
+	for k := range es {
+		e := make([]float32, t)
+		copy(e, m.OutB)
+		for y := 0; y < t; y++ {
+			row := m.Out[y*2*h : (y+1)*2*h]
+			e[y] += dot(row[:h], hf[k]) + dot(row[h:], hb[k])
+		}
+		es[k] = e
+	}
+
+	return es
+}
+
+// PredictBatch return the tag indexes of the batch of the texts,
+// the LSTM steps are run over the batch together
+func (m *Model) PredictBatch(texts []string) [][]int {
+	xs := make([][][]float32, len(texts))
+	for b, text := range texts {
+		ids := m.ids([]rune(text))
+		xs[b] = make([][]float32, len(ids))
+		for k, id := range ids {
+			xs[b][k] = m.Emb[id*m.Embed : (id+1)*m.Embed]
+		}
+	}
+
+	hf := m.Fw.Run(xs, false)
+	hb := m.Bw.Run(xs, true)
+
+	result := make([][]int, len(texts))
+	for b := range texts {
+		es := m.emissions(hf[b], hb[b])
+		if m.CRF {
+			result[b] = m.viterbi(es)
+			continue
+		}
+
+		result[b] = make([]int, len(es))
+		for k, e := range es {
+			result[b][k] = argmax(e)
+		}
+	}
+
+	return result
+}
+
+// TagBatch return the tags of the batch of the texts
+func (m *Model) TagBatch(texts []string) [][]string {
+	ids := m.PredictBatch(texts)
+	tags := make([][]string, len(ids))
+	for b := range ids {
+		tags[b] = make([]string, len(ids[b]))
+		for k, y := range ids[b] {
+			tags[b][k] = m.Tags[y]
+		}
+	}
+
+	return tags
+}
+
+// splitTag split the tag to the position and POS, such as "B-n"
+func splitTag(tag string) (string, string) {
+	if i := strings.IndexAny(tag, "-_"); i > 0 {
+		return tag[:i], tag[i+1:]
+	}
+	return tag, ""
+}
+
+// decode merge the runes to the words by the B, M, E, S tags
+func decode(runes []rune, tags []string) (words []gse.SegPos) {
+	begin := 0
+	for k := range runes {
+		position, pos := splitTag(tags[k])
+		end := k == len(runes)-1
+		if !end {
+			next, _ := splitTag(tags[k+1])
+			end = position == "E" || position == "S" || next == "B" || next == "S"
+		}
+
+		if end {
+			words = append(words, gse.SegPos{Text: string(runes[begin : k+1]), Pos: pos})
+			begin = k + 1
+		}
+	}
+
+	return
+}
+
+// PosBatch return the words with the POS of the batch of the texts
+func (m *Model) PosBatch(texts []string) [][]gse.SegPos {
+	tags := m.TagBatch(texts)
+	result := make([][]gse.SegPos, len(texts))
+	for b, text := range texts {
+		result[b] = decode([]rune(text), tags[b])
+	}
+
+	return result
+}
+
+// Pos return the words with the POS of the text
+func (m *Model) Pos(text string) []gse.SegPos {
+	return m.PosBatch([]string{text})[0]
+}
+
+// CutBatch cut the batch of the texts to words
+func (m *Model) CutBatch(texts []string) [][]string {
+	pos := m.PosBatch(texts)
+	result := make([][]string, len(pos))
+	for b := range pos {
+		result[b] = make([]string, len(pos[b]))
+		for k, p := range pos[b] {
+			result[b][k] = p.Text
+		}
+	}
+
+	return result
+}
+
+// Cut cut the text to words, can be used as the gse.Segmenter.OOVCut
+func (m *Model) Cut(text string) []string {
+	return m.CutBatch([]string{text})[0]
+}
@@ -0,0 +1,113 @@
+package rnn
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/go-ego/gse"
+	"github.com/vcaesar/tt"
+)
+
+var corpus = []string{
+	"我/r 喜欢/v 机器/n 学习/v",
+	"他/r 喜欢/v 学习/v",
+	"机器/n 学习/v 平台/n",
+	"我/r 的/u 平台/n",
+	"他/r 学习/v 机器/n",
+}
+
+func parse(text string) (words []gse.SegPos) {
+	for _, w := range strings.Fields(text) {
+		i := strings.Index(w, "/")
+		words = append(words, gse.SegPos{Text: w[:i], Pos: w[i+1:]})
+	}
+	return
+}
+
+func trainModel(pos bool, noCRF bool) *Model {
+	var samples []Sample
+	for _, text := range corpus {
+		words := parse(text)
+		if pos {
+			samples = append(samples, PosSample(words))
+			continue
+		}
+
+		var ws []string
+		for _, w := range words {
+			ws = append(ws, w.Text)
+		}
+		samples = append(samples, SegSample(ws))
+	}
+
+	return Train(samples, TrainOptions{Embed: 8, Hidden: 8,
+		Epochs: 60, LR: 0.1, NoCRF: noCRF, Seed: 1})
+}
+
+func TestSample(t *testing.T) {
+	s := SegSample([]string{"我", "机器学习"})
+	tt.Equal(t, "[S B M M E]", s.Tags)
+	tt.Equal(t, "我机器学习", string(s.Runes))
+
+	s = PosSample(parse("我/r 平台/n"))
+	tt.Equal(t, "[S-r B-n E-n]", s.Tags)
+
+	p := decode([]rune("我平台"), s.Tags)
+	tt.Equal(t, "[{我 r} {平台 n}]", p)
+}
+
+func TestTrainCut(t *testing.T) {
+	m := trainModel(false, false)
+	tt.Equal(t, "[我 喜欢 机器 学习]", m.Cut("我喜欢机器学习"))
+	tt.Equal(t, "[他 学习 平台]", m.Cut("他学习平台"))
+	tt.Equal(t, "[[机器 学习] [我 的 平台]]", m.CutBatch([]string{"机器学习", "我的平台"}))
+
+	m = trainModel(false, true)
+	tt.False(t, m.CRF)
+	tt.Equal(t, "[我 喜欢 机器 学习]", m.Cut("我喜欢机器学习"))
+}
+
+func TestTrainPos(t *testing.T) {
+	m := trainModel(true, false)
+	tt.Equal(t, "[{他 r} {喜欢 v} {机器 n} {学习 v}]", m.Pos("他喜欢机器学习"))
+}
+
+func TestReadWrite(t *testing.T) {
+	m := trainModel(false, false)
+
+	var buf bytes.Buffer
+	_, err := m.WriteTo(&buf)
+	tt.Nil(t, err)
+
+	m1, err := Read(&buf)
+	tt.Nil(t, err)
+	tt.Equal(t, m.Tags, m1.Tags)
+	tt.Equal(t, m.Trans, m1.Trans)
+	tt.Equal(t, m.Cut("我喜欢机器学习"), m1.Cut("我喜欢机器学习"))
+
+	_, err = Read(bytes.NewBufferString("model\n"))
+	tt.NotNil(t, err)
+
+	for _, h := range []string{
+		`{"embed": 0, "hidden": 2, "tags": ["a"]}`,
+		`{"embed": 2, "hidden": -1, "tags": ["a"]}`,
+		`{"embed": 2, "hidden": 2, "tags": []}`,
+		`{"embed": 4000000000000, "hidden": 2, "tags": ["a"]}`,
+		`{"embed": 2, "hidden": 4000000000, "tags": ["a"]}`,
+		`{"embed": 99999, "hidden": 99999, "tags": ["a"], "crf": true}`,
+	} {
+		_, err = Read(bytes.NewBufferString(magic + "\n" + h + "\n"))
+		tt.NotNil(t, err)
+	}
+}
+
+func TestOOVCut(t *testing.T) {
+	m := trainModel(false, false)
+
+	var seg gse.Segmenter
+	seg.SkipLog = true
+	seg.LoadDictStr("的 1000 uj")
+	seg.OOVCut = m.Cut
+	tt.Equal(t, "[他 喜欢 机器 学习]", seg.Cut("他喜欢机器学习", true))
+}
@@ -0,0 +1,402 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package rnn
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/go-ego/gse"
+)
+
+// Sample the training sample, the runes with the tags
+type Sample struct {
+	Runes []rune
+	Tags  []string
+}
+
+// SegSample make the segmentation sample of the words with the B, M, E, S tags
+func SegSample(words []string) Sample {
+	pos := make([]gse.SegPos, len(words))
+	for i, w := range words {
+		pos[i].Text = w
+	}
+	return PosSample(pos)
+}
+
+// PosSample make the POS tagging sample of the words,
+// the tags are such as "B-n", "E-n" and "S-v"
+func PosSample(words []gse.SegPos) (s Sample) {
+	for _, w := range words {
+		runes := []rune(w.Text)
+		for i := range runes {
+			tag := "M"
+			switch {
+			case len(runes) == 1:
+				tag = "S"
+			case i == 0:
+				tag = "B"
+			case i == len(runes)-1:
+				tag = "E"
+			}
+			if w.Pos != "" {
+				tag += "-" + w.Pos
+			}
+
+			s.Runes = append(s.Runes, runes[i])
+			s.Tags = append(s.Tags, tag)
+		}
+	}
+
+	return
+}
+
+// TrainOptions the training options
+type TrainOptions struct {
+	// Embed and Hidden the embedding and LSTM hidden size, default is 32
+	Embed, Hidden int
+	// Epochs the training epochs, default is 5
+	Epochs int
+	// LR the SGD learning rate, default is 0.05
+	LR float32
+	// Clip clip the gradients to [-Clip, Clip], default is 5
+	Clip float32
+	// NoCRF use the softmax output instead of the CRF
+	NoCRF bool
+	// Seed the random seed of the initialization and shuffle
+	Seed int64
+	// Logger log the loss of each epoch
+	Logger gse.Logger
+}
+
+func (opt *TrainOptions) init() {
+	if opt.Embed <= 0 {
+		opt.Embed = 32
+	}
+	if opt.Hidden <= 0 {
+		opt.Hidden = 32
+	}
+	if opt.Epochs <= 0 {
+		opt.Epochs = 5
+	}
+	if opt.LR <= 0 {
+		opt.LR = 0.05
+	}
+	if opt.Clip <= 0 {
+		opt.Clip = 5
+	}
+}
+
+// Train train a new model by the samples with the SGD,
+// it is slow and only for the small models
+func Train(samples []Sample, opts ...TrainOptions) *Model {
+	var opt TrainOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	opt.init()
+
+	runeSet := make(map[rune]bool)
+	tagSet := make(map[string]bool)
+	for _, s := range samples {
+		for i, r := range s.Runes {
+			runeSet[r] = true
+			tagSet[s.Tags[i]] = true
+		}
+	}
+
+	var vocab []rune
+	for r := range runeSet {
+		vocab = append(vocab, r)
+	}
+	sort.Slice(vocab, func(i, j int) bool { return vocab[i] < vocab[j] })
+
+	var tags []string
+	for t := range tagSet {
+		tags = append(tags, t)
+	}
+	sort.Strings(tags)
+
+	m := NewModel(vocab, tags, opt.Embed, opt.Hidden, !opt.NoCRF)
+	rnd := rand.New(rand.NewSource(opt.Seed))
+	uniform := func(p []float32, scale float64) {
+		for i := range p {
+			p[i] = float32((rnd.Float64()*2 - 1) * scale)
+		}
+	}
+
+	scale := 1 / math.Sqrt(float64(opt.Hidden))
+	uniform(m.Emb, 0.1)
+	for _, l := range []*LSTM{m.Fw, m.Bw} {
+		uniform(l.Wx, scale)
+		uniform(l.Wh, scale)
+		// the forget gate bias
+		for j := l.Hidden; j < 2*l.Hidden; j++ {
+			l.B[j] = 1
+		}
+	}
+	uniform(m.Out, scale)
+
+	m.Train(samples, opt)
+	return m
+}
+
+type lstmCache struct {
+	x, z, c, h [][]float32
+}
+
+// forward run the sequence and cache the states for the backward
+func (l *LSTM) forward(xs [][]float32) (cache lstmCache) {
+	c := make([]float32, l.Hidden)
+	h := make([]float32, l.Hidden)
+	for _, x := range xs {
+		z := make([]float32, 4*l.Hidden)
+		l.gates(z, x, h)
+
+		c = append([]float32(nil), c...)
+		h = append([]float32(nil), h...)
+		l.step(z, c, h)
+
+		cache.x = append(cache.x, x)
+		cache.z = append(cache.z, z)
+		cache.c = append(cache.c, c)
+		cache.h = append(cache.h, h)
+	}
+
+	return
+}
+
+// backward the back propagation through time,
+// add the gradients to g and return the input gradients
+func (l *LSTM) backward(cache lstmCache, dhs [][]float32, g *LSTM) [][]float32 {
+	n, in := l.Hidden, l.In
+	zero := make([]float32, n)
+	dhNext := make([]float32, n)
+	dcNext := make([]float32, n)
+	dz := make([]float32, 4*n)
+	dxs := make([][]float32, len(dhs))
+
+	for k := len(dhs) - 1; k >= 0; k-- {
+		z, c := cache.z[k], cache.c[k]
+		cPrev, hPrev := zero, zero
+		if k > 0 {
+			cPrev, hPrev = cache.c[k-1], cache.h[k-1]
+		}
+
+		for j := 0; j < n; j++ {
+			i, f, gc, o := z[j], z[n+j], z[2*n+j], z[3*n+j]
+			dh := dhs[k][j] + dhNext[j]
+			tc := tanh(c[j])
+
+			do := dh * tc
+			dc := dh*o*(1-tc*tc) + dcNext[j]
+			dcNext[j] = dc * f
+
+			dz[j] = dc * gc * i * (1 - i)
+			dz[n+j] = dc * cPrev[j] * f * (1 - f)
+			dz[2*n+j] = dc * i * (1 - gc*gc)
+			dz[3*n+j] = do * o * (1 - o)
+		}
+
+		dx := make([]float32, in)
+		for j := range dhNext {
+			dhNext[j] = 0
+		}
+		for j, d := range dz {
+			if d == 0 {
+				continue
+			}
+
+			g.B[j] += d
+			wx, gx := l.Wx[j*in:(j+1)*in], g.Wx[j*in:(j+1)*in]
+			for m := range wx {
+				gx[m] += d * cache.x[k][m]
+				dx[m] += d * wx[m]
+			}
+
+			wh, gh := l.Wh[j*n:(j+1)*n], g.Wh[j*n:(j+1)*n]
+			for m := range wh {
+				gh[m] += d * hPrev[m]
+				dhNext[m] += d * wh[m]
+			}
+		}
+		dxs[k] = dx
+	}
+
+	return dxs
+}
+
+// softmaxGrad return the cross entropy loss and the emission gradients
+func softmaxGrad(es [][]float32, gold []int) (float64, [][]float32) {
+	loss := 0.0
+	des := make([][]float32, len(es))
+	buf := make([]float64, 0)
+	for k, e := range es {
+		buf = buf[:0]
+		for _, v := range e {
+			buf = append(buf, float64(v))
+		}
+		logZ := logSumExp(buf)
+		loss += logZ - buf[gold[k]]
+
+		des[k] = make([]float32, len(e))
+		for y := range e {
+			des[k][y] = float32(math.Exp(buf[y] - logZ))
+		}
+		des[k][gold[k]]--
+	}
+
+	return loss, des
+}
+
+// grad run the forward and backward of the sample, add the gradients to g,
+// return the loss and the embedding indexes used
+func (m *Model) grad(ids, gold []int, g *Model) float64 {
+	n, h, e := len(ids), m.Hidden, m.Embed
+	xs := make([][]float32, n)
+	rxs := make([][]float32, n)
+	for k, id := range ids {
+		xs[k] = m.Emb[id*e : (id+1)*e]
+		rxs[n-1-k] = xs[k]
+	}
+
+	fc := m.Fw.forward(xs)
+	bc := m.Bw.forward(rxs)
+	hb := make([][]float32, n)
+	for k := range hb {
+		hb[k] = bc.h[n-1-k]
+	}
+	es := m.emissions(fc.h, hb)
+
+	var (
+		loss float64
+		des  [][]float32
+	)
+	if m.CRF {
+		loss, des = m.crfGrad(es, gold, g)
+	} else {
+		loss, des = softmaxGrad(es, gold)
+	}
+
+	dhf := make([][]float32, n)
+	dhb := make([][]float32, n)
+	for k := 0; k < n; k++ {
+		dhf[k] = make([]float32, h)
+		dhb[n-1-k] = make([]float32, h)
+		for y, d := range des[k] {
+			g.OutB[y] += d
+			row := m.Out[y*2*h : (y+1)*2*h]
+			grow := g.Out[y*2*h : (y+1)*2*h]
+			for j := 0; j < h; j++ {
+				grow[j] += d * fc.h[k][j]
+				grow[h+j] += d * hb[k][j]
+				dhf[k][j] += d * row[j]
+				dhb[n-1-k][j] += d * row[h+j]
+			}
+		}
+	}
+
+	dxf := m.Fw.backward(fc, dhf, g.Fw)
+	dxb := m.Bw.backward(bc, dhb, g.Bw)
+	for k, id := range ids {
+		row := g.Emb[id*e : (id+1)*e]
+		for j := range row {
+			row[j] += dxf[k][j] + dxb[n-1-k][j]
+		}
+	}
+
+	return loss
+}
+
+// update the parameters by the gradients and reset the gradients
+func update(p, g []float32, lr, clip float32) {
+	for i, d := range g {
+		if d == 0 {
+			continue
+		}
+		if d > clip {
+			d = clip
+		} else if d < -clip {
+			d = -clip
+		}
+
+		p[i] -= lr * d
+		g[i] = 0
+	}
+}
+
+// Train continue training the model by the samples,
+// the runes and tags not in the model are ignored,
+// return the average loss of the last epoch
+func (m *Model) Train(samples []Sample, opts ...TrainOptions) float64 {
+	var opt TrainOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	opt.init()
+
+	tagIndex := make(map[string]int, len(m.Tags))
+	for i, t := range m.Tags {
+		tagIndex[t] = i
+	}
+
+	type item struct{ ids, gold []int }
+	var items []item
+	for _, s := range samples {
+		var it item
+		for i, r := range s.Runes {
+			y, ok := tagIndex[s.Tags[i]]
+			if !ok {
+				continue
+			}
+			it.ids = append(it.ids, m.vocab[r])
+			it.gold = append(it.gold, y)
+		}
+
+		if len(it.ids) > 0 {
+			items = append(items, it)
+		}
+	}
+
+	g := NewModel(m.runes, m.Tags, m.Embed, m.Hidden, m.CRF)
+	params, grads := m.params(), g.params()
+	rnd := rand.New(rand.NewSource(opt.Seed))
+
+	loss := 0.0
+	for epoch := 0; epoch < opt.Epochs; epoch++ {
+		start := time.Now()
+		rnd.Shuffle(len(items), func(i, j int) { items[i], items[j] = items[j], items[i] })
+
+		loss = 0.0
+		for _, it := range items {
+			loss += m.grad(it.ids, it.gold, g)
+			for i := range params {
+				update(params[i], grads[i], opt.LR, opt.Clip)
+			}
+		}
+		if len(items) > 0 {
+			loss /= float64(len(items))
+		}
+
+		if opt.Logger != nil {
+			opt.Logger.Info("rnn train epoch", "epoch", epoch+1,
+				"loss", loss, "duration", time.Since(start))
+		}
+	}
+
+	return loss
+}
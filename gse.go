@@ -129,8 +129,12 @@ func (seg *Segmenter) LoadModel(prob ...map[rune]float64) {
 	hmm.LoadModel(prob...)
 }
 
-// HMMCut cut sentence string use HMM with Viterbi
+// HMMCut cut sentence string use HMM with Viterbi,
+// use the seg.OOVCut instead if it is not nil
 func (seg *Segmenter) HMMCut(str string, reg ...*regexp.Regexp) []string {
+	if seg.OOVCut != nil {
+		return seg.OOVCut(str)
+	}
 	// hmm.LoadModel(prob...)
 	return hmm.Cut(str, reg...)
 }
@@ -0,0 +1,206 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package idf
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-ego/gse"
+)
+
+// BuilderOptions the options of the IDF builder
+type BuilderOptions struct {
+	// MinDF the minimum number of the documents containing the word,
+	// default is 1
+	MinDF int
+	// MaxDF the maximum ratio of the documents containing the word,
+	// the too common words are dropped, default is 1.0 (keep all)
+	MaxDF float64
+	// Smooth the additive smoothing of the document counts, default is 1.0
+	Smooth float64
+	// MinLen the minimum word length in runes, default is 2,
+	// the same as ExtractTags
+	MinLen int
+}
+
+// Builder build the IDF dictionary from a document collection,
+// the documents can be added incrementally and the IDF rebuilt at any time
+type Builder struct {
+	seg *gse.Segmenter
+	opt BuilderOptions
+
+	docs int
+	df   map[string]int
+}
+
+// NewBuilder create a new IDF builder with the segmenter
+func NewBuilder(seg *gse.Segmenter, opts ...BuilderOptions) *Builder {
+	var opt BuilderOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	if opt.MinDF <= 0 {
+		opt.MinDF = 1
+	}
+	if opt.MaxDF <= 0 || opt.MaxDF > 1 {
+		opt.MaxDF = 1.0
+	}
+	if opt.Smooth <= 0 {
+		opt.Smooth = 1.0
+	}
+	if opt.MinLen <= 0 {
+		opt.MinLen = 2
+	}
+
+	return &Builder{seg: seg, opt: opt, df: make(map[string]int)}
+}
+
+// isWord the word has letters or numbers and no spaces
+func isWord(w string) bool {
+	if strings.IndexFunc(w, unicode.IsSpace) >= 0 {
+		return false
+	}
+
+	for _, r := range w {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Add add a document, each word is counted once for the document
+func (b *Builder) Add(doc string) {
+	b.docs++
+
+	seen := make(map[string]bool)
+	for _, w := range b.seg.Cut(doc, true) {
+		w = strings.TrimSpace(w)
+		if seen[w] || utf8.RuneCountInString(w) < b.opt.MinLen || !isWord(w) {
+			continue
+		}
+
+		seen[w] = true
+		b.df[w]++
+	}
+}
+
+// AddReader add the documents from the reader, one document for each line
+func (b *Builder) AddReader(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			b.Add(line)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// Docs return the number of the documents added
+func (b *Builder) Docs() int {
+	return b.docs
+}
+
+// DF return the number of the documents containing the word
+func (b *Builder) DF(word string) int {
+	return b.df[word]
+}
+
+// idf the smoothed IDF: log((docs + smooth) / (df + smooth)) + 1
+func (b *Builder) idf(df int) float64 {
+	s := b.opt.Smooth
+	return math.Log((float64(b.docs)+s)/(float64(df)+s)) + 1
+}
+
+// Words return the words passed the min-df and max-df cuts
+// with the IDFs, sorted by word
+func (b *Builder) Words() (words []string, idfs []float64) {
+	maxDF := b.opt.MaxDF * float64(b.docs)
+	for w, df := range b.df {
+		if df >= b.opt.MinDF && float64(df) <= maxDF {
+			words = append(words, w)
+		}
+	}
+
+	sort.Strings(words)
+	idfs = make([]float64, len(words))
+	for i, w := range words {
+		idfs[i] = b.idf(b.df[w])
+	}
+
+	return
+}
+
+// Idf build a new Idf from the documents added,
+// can be used as the TagExtracter.Idf directly
+func (b *Builder) Idf() *Idf {
+	i := NewIdf(IdfOptions{All: true})
+	i.seg.SkipLog = b.seg.SkipLog
+	i.seg.Logger = b.seg.Logger
+
+	words, idfs := b.Words()
+	for k, w := range words {
+		i.AddToken(w, idfs[k])
+	}
+	i.seg.CalcToken()
+
+	return i
+}
+
+// WriteTo write the IDF dictionary to w, the format is the same
+// as the "idf.txt" and can be loaded by LoadIdf
+func (b *Builder) WriteTo(w io.Writer) (int64, error) {
+	var n int64
+	bw := bufio.NewWriter(w)
+
+	words, idfs := b.Words()
+	for k, word := range words {
+		c, err := bw.WriteString(word + " " +
+			strconv.FormatFloat(idfs[k], 'f', 6, 64) + "\n")
+		n += int64(c)
+		if err != nil {
+			return n, err
+		}
+	}
+
+	return n, bw.Flush()
+}
+
+// Save write the IDF dictionary to the file
+func (b *Builder) Save(file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.WriteTo(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	return err
+}
@@ -0,0 +1,86 @@
+package idf
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-ego/gse"
+	"github.com/vcaesar/tt"
+)
+
+func TestMedian(t *testing.T) {
+	i := NewIdf(IdfOptions{All: true})
+	for _, f := range []float64{5, 1, 3, 9, 7, 2} {
+		i.AddToken(strings.Repeat("词", int(f)), f)
+	}
+	// sorted: 1 2 3 5 7 9
+	tt.Equal(t, 5, i.Median())
+
+	i.AddToken("词词词", 100)
+	tt.Equal(t, 5, i.Median())
+	tt.Equal(t, 6, i.NumTokens())
+
+	err := i.LoadDictStr("湖面 4.5\n空气 0.8")
+	tt.Nil(t, err)
+	tt.Equal(t, 4.5, i.Median())
+	f, _, ok := i.Freq("空气")
+	tt.True(t, ok)
+	tt.Equal(t, 0.8, f)
+}
+
+func TestBuilder(t *testing.T) {
+	var seg gse.Segmenter
+	seg.SkipLog = true
+	err := seg.LoadDictStr("湖面 100 n\n空气 100 n\n宁静 100 a\n" +
+		"那里 100 r\n总是 100 d\n澄清 100 v\n充满 100 v")
+	tt.Nil(t, err)
+
+	docs := "那里湖面总是澄清\n那里空气充满宁静\n\n那里湖面宁静, 那里宁静\n"
+	b := NewBuilder(&seg)
+	err = b.AddReader(strings.NewReader(docs))
+	tt.Nil(t, err)
+	tt.Equal(t, 3, b.Docs())
+	tt.Equal(t, 3, b.DF("那里"))
+	tt.Equal(t, 2, b.DF("宁静"))
+	tt.Equal(t, 1, b.DF("空气"))
+
+	words, idfs := b.Words()
+	tt.Equal(t, "[充满 宁静 总是 湖面 澄清 空气 那里]", words)
+	tt.Equal(t, 1, idfs[6])
+	tt.True(t, idfs[5] > idfs[1])
+
+	b1 := NewBuilder(&seg, BuilderOptions{MinDF: 2, MaxDF: 0.9})
+	b1.AddReader(strings.NewReader(docs))
+	words, _ = b1.Words()
+	tt.Equal(t, "[宁静 湖面]", words)
+
+	i := b.Idf()
+	tt.Equal(t, 7, i.NumTokens())
+	f, _, ok := i.Freq("空气")
+	tt.True(t, ok)
+	tt.Equal(t, idfs[5], f)
+	tt.Equal(t, idfs[0], i.Median())
+
+	var buf bytes.Buffer
+	_, err = b.WriteTo(&buf)
+	tt.Nil(t, err)
+	tt.True(t, strings.HasPrefix(buf.String(), "充满 1.693147\n"))
+
+	file := filepath.Join(t.TempDir(), "idf.txt")
+	err = b.Save(file)
+	tt.Nil(t, err)
+
+	var te TagExtracter
+	te.WithGse(seg)
+	te.Idf = NewIdf(IdfOptions{All: true})
+	err = te.Idf.LoadDict(file)
+	tt.Nil(t, err)
+	tt.Equal(t, 7, te.Idf.NumTokens())
+	tt.Equal(t, 1.693147, te.Idf.Median())
+
+	b.Add("湖面空气")
+	tt.Equal(t, 4, b.Docs())
+	tt.Equal(t, 2, b.DF("空气"))
+}
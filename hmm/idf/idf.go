@@ -15,28 +15,104 @@
 package idf
 
 import (
-	"sort"
+	"container/heap"
+	"math"
 
 	"github.com/go-ego/gse"
 )
 
+// floatHeap the min heap of float64
+type floatHeap []float64
+
+func (h floatHeap) Len() int            { return len(h) }
+func (h floatHeap) Less(i, j int) bool  { return h[i] < h[j] }
+func (h floatHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *floatHeap) Push(x interface{}) { *h = append(*h, x.(float64)) }
+func (h *floatHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// medianHeap maintain the median of the values by two heaps,
+// lower is the max heap (stored negated) of the smaller half,
+// upper is the min heap of the larger half
+type medianHeap struct {
+	lower, upper floatHeap
+}
+
+func (m *medianHeap) push(v float64) {
+	if len(m.upper) > 0 && v < m.upper[0] {
+		heap.Push(&m.lower, -v)
+	} else {
+		heap.Push(&m.upper, v)
+	}
+
+	// keep len(lower) <= len(upper) <= len(lower)+1
+	if len(m.upper) > len(m.lower)+1 {
+		heap.Push(&m.lower, -heap.Pop(&m.upper).(float64))
+	} else if len(m.lower) > len(m.upper) {
+		heap.Push(&m.upper, -heap.Pop(&m.lower).(float64))
+	}
+}
+
+// median return the upper median, the same as sorted[len/2]
+func (m *medianHeap) median() float64 {
+	if len(m.upper) == 0 {
+		return 0
+	}
+	return m.upper[0]
+}
+
+// IdfOptions the Idf options
+type IdfOptions struct {
+	// All load all the positive IDFs, not dropped by the segmenter
+	// MinTokenFreq, and keep the median of the loaded IDFs for the
+	// words not in the dictionary, such as the Builder IDFs
+	All bool
+}
+
 // Idf type a dictionary for all words with the
 // IDFs(Inverse Document Frequency).
 type Idf struct {
 	median float64
-	freqs  []float64
+	freqs  medianHeap
+	opt    IdfOptions
 
 	seg gse.Segmenter
 }
 
+func (i *Idf) init() {
+	if i.seg.Dict == nil {
+		i.seg.Dict = gse.NewDict()
+		i.seg.Load = true
+		i.seg.Init()
+	}
+}
+
 // AddToken add a new word with IDF into the dictionary.
 func (i *Idf) AddToken(text string, freq float64, pos ...string) error {
+	i.init()
+	num := i.seg.Dict.NumTokens()
 	err := i.seg.AddToken(text, freq, pos...)
+	if err != nil || i.seg.Dict.NumTokens() == num {
+		return err
+	}
 
-	i.freqs = append(i.freqs, freq)
-	sort.Float64s(i.freqs)
-	i.median = i.freqs[len(i.freqs)/2]
-	return err
+	i.freqs.push(freq)
+	i.median = i.freqs.median()
+	return nil
+}
+
+// calcMedian calculate the median of all the IDFs in the dictionary
+func (i *Idf) calcMedian() {
+	i.freqs = medianHeap{}
+	for _, token := range i.seg.Dict.LiveTokens() {
+		i.freqs.push(token.Freq())
+	}
+	i.median = i.freqs.median()
 }
 
 // LoadDict load the idf dictionary
@@ -45,14 +121,34 @@ func (i *Idf) LoadDict(files ...string) error {
 		files = i.seg.GetIdfPath(files...)
 	}
 
-	return i.seg.LoadDict(files...)
+	err := i.seg.LoadDict(files...)
+	if i.opt.All {
+		i.calcMedian()
+	}
+	return err
+}
+
+// LoadDictStr load the idf dictionary from the string
+func (i *Idf) LoadDictStr(str string) error {
+	i.init()
+	err := i.seg.LoadDictStr(str)
+	if i.opt.All {
+		i.calcMedian()
+	}
+	return err
 }
 
-// Freq return the IDF of the word
+// Freq return the IDF of the word, the prefix of the dictionary
+// words is found with the IDF 0
 func (i *Idf) Freq(key string) (float64, string, bool) {
 	return i.seg.Find(key)
 }
 
+// Median return the median IDF, used for the words not in the dictionary
+func (i *Idf) Median() float64 {
+	return i.median
+}
+
 // NumTokens return the IDF tokens' num
 func (i *Idf) NumTokens() int {
 	return i.seg.Dict.NumTokens()
@@ -63,7 +159,15 @@ func (i *Idf) TotalFreq() float64 {
 	return i.seg.Dict.TotalFreq()
 }
 
-// NewIdf create a new Idf
-func NewIdf() *Idf {
-	return &Idf{freqs: make([]float64, 0)}
+// NewIdf create a new Idf, the median is only updated by the AddToken
+// by the default, see the IdfOptions.All
+func NewIdf(opts ...IdfOptions) *Idf {
+	i := &Idf{}
+	if len(opts) > 0 {
+		i.opt = opts[0]
+	}
+	if i.opt.All {
+		i.seg.MinTokenFreq = math.SmallestNonzeroFloat64
+	}
+	return i
 }
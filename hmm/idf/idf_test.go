@@ -28,3 +28,34 @@ func TestExtAndRank(t *testing.T) {
 	results := tr.TextRank(text, 5)
 	fmt.Println("results: ", results)
 }
+
+func TestExtractTagsWeight(t *testing.T) {
+	var seg gse.Segmenter
+	seg.SkipLog = true
+	seg.LoadDictStr("的 1000 uj\n空气 100 n\n湖面 100 n\n宁静 100 a")
+
+	// the default loading, 空气 is less than the MinTokenFreq 2.0
+	var te TagExtracter
+	te.WithGse(seg)
+	err := te.LoadIdfStr("空气 1.5\n湖面 3\n澄清 6")
+	tt.Nil(t, err)
+	tt.Equal(t, 2, te.Idf.NumTokens())
+	tt.Equal(t, 0, te.Idf.Median())
+	tags := te.ExtractTags("空气湖面宁静湖面", 1)
+	tt.Equal(t, "[{湖面 1.5}]", tags)
+
+	// 宁静 is not in the IDF dictionary and 空气 is the prefix of
+	// 空气质量, they are weighted by the median
+	te.Idf = NewIdf(IdfOptions{All: true})
+	err = te.Idf.LoadDictStr("空气质量 1.5\n湖面 3\n澄清 6")
+	tt.Nil(t, err)
+	tt.Equal(t, 3, te.Idf.NumTokens())
+	tt.Equal(t, 3, te.Idf.Median())
+
+	tags = te.ExtractTags("空气湖面宁静湖面", 5)
+	weights := make(map[string]float64)
+	for _, tag := range tags {
+		weights[tag.Text] = tag.Weight
+	}
+	tt.Equal(t, "map[宁静:0.75 湖面:1.5 空气:0.75]", weights)
+}
@@ -0,0 +1,246 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package idf
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/go-ego/gse"
+	"github.com/go-ego/gse/hmm/segment"
+)
+
+var (
+	// DefaultPhrasePos the default POS can be in the keyphrase,
+	// the adjectives and nouns
+	DefaultPhrasePos = []string{"a", "an", "n", "nr", "ns", "nt", "nz", "vn", "eng"}
+	// DefaultPhraseHeadPos the default POS can end the keyphrase, the nouns
+	DefaultPhraseHeadPos = []string{"n", "nr", "ns", "nt", "nz", "vn", "eng"}
+)
+
+// PhraseOptions the options of the keyphrase extraction
+type PhraseOptions struct {
+	// Pos the POS can be in the phrase, default is DefaultPhrasePos
+	Pos []string
+	// HeadPos the POS can end the phrase, default is DefaultPhraseHeadPos
+	HeadPos []string
+	// MaxWords the maximum number of the words in a phrase, default is 4
+	MaxWords int
+	// MinScore the minimum word score relative to the top one, default is 0
+	MinScore float64
+}
+
+// Phrase the keyphrase merged by the adjacent keywords
+type Phrase struct {
+	Text   string
+	Weight float64
+	Words  []string
+	// Positions the bytes start of each occurrence in the text
+	Positions []int
+}
+
+func (opt *PhraseOptions) init() {
+	if len(opt.Pos) == 0 {
+		opt.Pos = DefaultPhrasePos
+	}
+	if len(opt.HeadPos) == 0 {
+		opt.HeadPos = DefaultPhraseHeadPos
+	}
+	if opt.MaxWords <= 0 {
+		opt.MaxWords = 4
+	}
+}
+
+func toSet(list []string) map[string]bool {
+	m := make(map[string]bool, len(list))
+	for _, s := range list {
+		m[s] = true
+	}
+	return m
+}
+
+// offsets return the bytes start of each word in the text, -1 is not found,
+// the words may be lower case by the segmenter
+func offsets(text string, words []string) []int {
+	if lower := strings.ToLower(text); len(lower) == len(text) {
+		text = lower
+	}
+
+	offs := make([]int, len(words))
+	off := 0
+	for i, w := range words {
+		k := strings.Index(text[off:], w)
+		if k < 0 {
+			offs[i] = -1
+			continue
+		}
+
+		offs[i] = off + k
+		off += k + len(w)
+	}
+
+	return offs
+}
+
+// containsWords the words contain the sub words sequence
+func containsWords(words, sub []string) bool {
+	for i := 0; i+len(sub) <= len(words); i++ {
+		match := true
+		for j := range sub {
+			if words[i+j] != sub[j] {
+				match = false
+				break
+			}
+		}
+
+		if match {
+			return true
+		}
+	}
+
+	return false
+}
+
+// keyphrases merge the adjacent scored words following the POS patterns,
+// the phrase weight is the sum of the word scores,
+// the phrases contained by a higher weight one are removed
+func keyphrases(text string, pairs []gse.SegPos, scores segment.Segments,
+	topK int, opt PhraseOptions) []Phrase {
+	opt.init()
+	inPos := toSet(opt.Pos)
+	headPos := toSet(opt.HeadPos)
+
+	score := make(map[string]float64, len(scores))
+	max := 0.0
+	for _, s := range scores {
+		score[s.Text] = s.Weight
+		max = math.Max(max, s.Weight)
+	}
+	min := opt.MinScore * max
+
+	words := make([]string, len(pairs))
+	for i, p := range pairs {
+		words[i] = p.Text
+	}
+	offs := offsets(text, words)
+	phrases := make(map[string]*Phrase)
+
+	addRun := func(run []int) {
+		// the phrase ends with the head word
+		for len(run) > 0 && !headPos[pairs[run[len(run)-1]].Pos] {
+			run = run[:len(run)-1]
+		}
+		if len(run) > opt.MaxWords {
+			run = run[len(run)-opt.MaxWords:]
+		}
+		if len(run) == 0 {
+			return
+		}
+
+		first, last := run[0], run[len(run)-1]
+		if offs[first] < 0 || offs[last] < 0 {
+			return
+		}
+
+		phraseText := text[offs[first] : offs[last]+len(pairs[last].Text)]
+		p, ok := phrases[phraseText]
+		if !ok {
+			p = &Phrase{Text: phraseText}
+			for _, i := range run {
+				p.Words = append(p.Words, pairs[i].Text)
+				p.Weight += score[pairs[i].Text]
+			}
+			phrases[phraseText] = p
+		}
+
+		p.Positions = append(p.Positions, offs[first])
+	}
+
+	var run []int
+	for i, p := range pairs {
+		s, ok := score[p.Text]
+		if ok && s > 0 && s >= min && inPos[p.Pos] {
+			run = append(run, i)
+			continue
+		}
+
+		// the spaces between the words, such as "machine learning"
+		if len(run) > 0 && strings.TrimSpace(p.Text) == "" {
+			continue
+		}
+
+		addRun(run)
+		run = run[:0]
+	}
+	addRun(run)
+
+	list := make([]*Phrase, 0, len(phrases))
+	for _, p := range phrases {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Weight == list[j].Weight {
+			return list[i].Text < list[j].Text
+		}
+		return list[i].Weight > list[j].Weight
+	})
+
+	var result []Phrase
+	for _, p := range list {
+		dup := false
+		for _, r := range result {
+			if containsWords(r.Words, p.Words) {
+				dup = true
+				break
+			}
+		}
+
+		if !dup {
+			result = append(result, *p)
+		}
+		if topK > 0 && len(result) >= topK {
+			break
+		}
+	}
+
+	return result
+}
+
+// Keyphrases extract the topK keyphrases from text by the TF-IDF,
+// the adjacent keywords following the POS patterns are merged, such as
+// "机器 学习 平台" to "机器学习平台"
+func (t *TagExtracter) Keyphrases(text string, topK int, opts ...PhraseOptions) []Phrase {
+	var opt PhraseOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	scores := t.ExtractTags(text, math.MaxInt32)
+	return keyphrases(text, t.seg.Pos(text), scores, topK, opt)
+}
+
+// Keyphrases extract the topK keyphrases from text by the TextRank,
+// the words of the phrase POS are ranked and the adjacent ones are merged
+func (t *TextRanker) Keyphrases(text string, topK int, opts ...PhraseOptions) []Phrase {
+	var opt PhraseOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	opt.init()
+
+	scores := t.TextRankWithPOS(text, 0, opt.Pos)
+	return keyphrases(text, t.seg.Cut(text, true), scores, topK, opt)
+}
@@ -0,0 +1,47 @@
+package idf
+
+import (
+	"testing"
+
+	"github.com/go-ego/gse"
+	"github.com/vcaesar/tt"
+)
+
+func TestKeyphrases(t *testing.T) {
+	var seg gse.Segmenter
+	seg.SkipLog = true
+	err := seg.LoadDictStr("的 1000 uj\n机器 50 n\n学习 50 vn\n平台 50 n\n" +
+		"深度 40 n\n模型 40 n\n支持 60 v\n我们 100 r\n新 80 a\n很 100 d\n好 100 a")
+	tt.Nil(t, err)
+
+	text := "我们的机器学习平台很好, 新的机器学习平台支持深度学习模型"
+
+	var te TagExtracter
+	te.WithGse(seg)
+	err = te.LoadIdfStr("机器 8\n学习 6\n平台 7\n深度 9\n模型 8\n支持 3\n我们 2")
+	tt.Nil(t, err)
+
+	phrases := te.Keyphrases(text, 5)
+	tt.Equal(t, 2, len(phrases))
+	tt.Equal(t, "机器学习平台", phrases[0].Text)
+	tt.Equal(t, "[机器 学习 平台]", phrases[0].Words)
+	tt.Equal(t, "[9 41]", phrases[0].Positions)
+	tt.Equal(t, "深度学习模型", phrases[1].Text)
+	tt.Equal(t, "[65]", phrases[1].Positions)
+	tt.True(t, phrases[0].Weight > phrases[1].Weight)
+
+	phrases = te.Keyphrases(text, 1, PhraseOptions{MaxWords: 2})
+	tt.Equal(t, 1, len(phrases))
+	tt.Equal(t, "[学习 平台]", phrases[0].Words)
+
+	var tr TextRanker
+	tr.WithGse(seg)
+	phrases = tr.Keyphrases(text, 3)
+	tt.Equal(t, "机器学习平台", phrases[0].Text)
+	tt.Equal(t, "[9 41]", phrases[0].Positions)
+
+	phrases = tr.Keyphrases("machine learning 平台, 平台", 0)
+	tt.Equal(t, 1, len(phrases))
+	tt.Equal(t, "machine learning 平台", phrases[0].Text)
+	tt.Equal(t, "[machine learning 平台]", phrases[0].Words)
+}
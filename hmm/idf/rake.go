@@ -0,0 +1,186 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package idf
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-ego/gse"
+	"github.com/go-ego/gse/hmm/segment"
+)
+
+// Extracter the keyword extractor,
+// TagExtracter, TextRanker, Rake and Yake can be swapped by it
+type Extracter interface {
+	ExtractTags(text string, topK int) segment.Segments
+}
+
+// ExtractTags extract the topK key words from text by the TextRank
+func (t *TextRanker) ExtractTags(text string, topK int) segment.Segments {
+	return t.TextRank(text, topK)
+}
+
+// extractor the segmenter and stop words shared by Rake and Yake
+type extractor struct {
+	seg      gse.Segmenter
+	stopWord *StopWord
+}
+
+// WithGse register the gse segmenter
+func (e *extractor) WithGse(segs gse.Segmenter) {
+	e.stopWord = NewStopWord()
+	e.seg = segs
+}
+
+// LoadStopWords load and create a new StopWord dictionary from the file.
+func (e *extractor) LoadStopWords(fileName ...string) error {
+	e.stopWord = NewStopWord()
+	e.stopWord.seg.Logger = e.seg.Logger
+	return e.stopWord.LoadDict(fileName...)
+}
+
+// AddStop add a stop word
+func (e *extractor) AddStop(text ...string) {
+	if e.stopWord == nil {
+		e.stopWord = NewStopWord()
+	}
+
+	for _, s := range text {
+		e.stopWord.AddStop(s)
+	}
+}
+
+func (e *extractor) isStop(word string) bool {
+	return e.stopWord != nil && e.stopWord.IsStopWord(strings.ToLower(word))
+}
+
+// words cut the text, return the words and the original text of them
+func (e *extractor) words(text string) (words, origin []string) {
+	words = e.seg.Cut(text, true)
+	origin = make([]string, len(words))
+
+	offs := offsets(text, words)
+	for i, w := range words {
+		origin[i] = w
+		if offs[i] >= 0 {
+			origin[i] = text[offs[i] : offs[i]+len(w)]
+		}
+	}
+
+	return
+}
+
+// phraseText join the words, keep the space between the alphanumeric words
+func phraseText(words []string) string {
+	var b strings.Builder
+	for i, w := range words {
+		if i > 0 {
+			r1, _ := utf8.DecodeLastRuneInString(words[i-1])
+			r2, _ := utf8.DecodeRuneInString(w)
+			if r1 < utf8.RuneSelf && r2 < utf8.RuneSelf {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(w)
+	}
+
+	return b.String()
+}
+
+// sortSegments sort the segments by weight and return the topK
+func sortSegments(ws segment.Segments, topK int) segment.Segments {
+	sort.Sort(sort.Reverse(ws))
+	if topK > 0 && len(ws) > topK {
+		return ws[:topK]
+	}
+
+	return ws
+}
+
+// Rake the RAKE (Rapid Automatic Keyword Extraction) extractor,
+// the candidate phrases are the words delimited by the stop words
+// and punctuations, scored by the word degree and frequency,
+// it needs no corpus.
+type Rake struct {
+	extractor
+	// MaxWords the maximum number of the words in a phrase, 0 is no limit
+	MaxWords int
+}
+
+// ExtractTags extract the topK keyphrases from text
+func (r *Rake) ExtractTags(text string, topK int) segment.Segments {
+	words, _ := r.words(text)
+
+	var (
+		phrases [][]string
+		run     []string
+	)
+	flush := func() {
+		if len(run) > 0 {
+			phrases = append(phrases, run)
+			run = nil
+		}
+	}
+
+	for _, w := range words {
+		if strings.TrimSpace(w) == "" {
+			// the spaces between the words
+			continue
+		}
+
+		if !isWord(w) || r.isStop(w) {
+			flush()
+			continue
+		}
+
+		run = append(run, strings.ToLower(w))
+		if r.MaxWords > 0 && len(run) >= r.MaxWords {
+			flush()
+		}
+	}
+	flush()
+
+	freq := make(map[string]float64)
+	degree := make(map[string]float64)
+	for _, p := range phrases {
+		for _, w := range p {
+			freq[w]++
+			degree[w] += float64(len(p))
+		}
+	}
+
+	scores := make(map[string]float64)
+	for _, p := range phrases {
+		text := phraseText(p)
+		if _, ok := scores[text]; ok || utf8.RuneCountInString(text) < 2 {
+			continue
+		}
+
+		score := 0.0
+		for _, w := range p {
+			score += degree[w] / freq[w]
+		}
+		scores[text] = score
+	}
+
+	ws := make(segment.Segments, 0, len(scores))
+	for text, score := range scores {
+		ws = append(ws, segment.Segment{Text: text, Weight: score})
+	}
+
+	return sortSegments(ws, topK)
+}
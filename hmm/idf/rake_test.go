@@ -0,0 +1,69 @@
+package idf
+
+import (
+	"testing"
+
+	"github.com/go-ego/gse"
+	"github.com/vcaesar/tt"
+)
+
+const rakeText = "Compatibility of systems of linear constraints over the set of natural numbers. " +
+	"Criteria of compatibility of a system of linear Diophantine equations, " +
+	"strict inequations, and nonstrict inequations are considered. " +
+	"Upper bounds for components of a minimal set of solutions and algorithms of " +
+	"construction of minimal generating sets of solutions for all types of systems are given."
+
+func newKeywordSeg() gse.Segmenter {
+	var seg gse.Segmenter
+	seg.SkipLog = true
+	seg.LoadDictStr("机器 50 n\n学习 50 vn\n平台 50 n\n的 1000 uj\n是 1000 v\n一个 100 m")
+	return seg
+}
+
+func TestRake(t *testing.T) {
+	var r Rake
+	r.WithGse(newKeywordSeg())
+	r.AddStop("a", "for", "over", "are", "given", "considered", "upper", "types", "criteria")
+
+	tags := r.ExtractTags(rakeText, 5)
+	tt.Equal(t, 5, len(tags))
+	tt.Equal(t, "minimal generating sets", tags[0].Text)
+	tt.Equal(t, 8.5, tags[0].Weight)
+	tt.Equal(t, "linear diophantine equations", tags[1].Text)
+
+	var e Extracter = &r
+	r.AddStop("是", "的", "一个")
+	tags = e.ExtractTags("机器学习是一个平台的机器学习", 0)
+	tt.Equal(t, "[{机器学习 4} {平台 1}]", tags)
+
+	r.MaxWords = 2
+	tags = r.ExtractTags(rakeText, 0)
+	for _, tag := range tags {
+		tt.NotEqual(t, "minimal generating sets", tag.Text)
+	}
+}
+
+func TestYake(t *testing.T) {
+	var y Yake
+	y.WithGse(newKeywordSeg())
+	y.AddStop("a", "for", "over", "are")
+
+	tags := y.ExtractTags(rakeText, 10)
+	tt.Equal(t, 10, len(tags))
+	for _, tag := range tags {
+		tt.True(t, tag.Weight > 0 && tag.Weight < 1)
+	}
+
+	text := "Google is acquiring Kaggle. Kaggle is a platform for data science. " +
+		"Google bought Kaggle, the data science community."
+	y.AddStop("is", "bought")
+	tags = y.ExtractTags(text, 4)
+	tt.Equal(t, "[acquiring kaggle kaggle data science google]", []string{
+		tags[0].Text, tags[1].Text, tags[2].Text, tags[3].Text})
+	tt.True(t, tags[0].Weight > tags[3].Weight)
+
+	var e Extracter = &y
+	y.AddStop("是", "的", "一个")
+	tags = e.ExtractTags("机器学习是一个平台. 机器学习", 0)
+	tt.Equal(t, "机器学习", tags[0].Text)
+}
@@ -0,0 +1,192 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package idf
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-ego/gse"
+)
+
+// SummaryOptions the options of the summarization
+type SummaryOptions struct {
+	// Sentences the maximum number of the sentences, default is 3
+	Sentences int
+	// MaxLen the length budget of the summary in characters, 0 is no limit
+	MaxLen int
+	// TextRank the options of the sentence ranking
+	TextRank TextRankOptions
+}
+
+// Summarizer the extractive summarizer, the sentences are ranked
+// by the TextRank of the sentence similarity graph, the similarity is
+// the TF-IDF cosine when the Idf is loaded, otherwise the token overlap
+type Summarizer struct {
+	extractor
+	Idf *Idf
+}
+
+// LoadIdf load and create a new Idf dictionary from the file.
+func (s *Summarizer) LoadIdf(fileName ...string) error {
+	s.Idf = NewIdf()
+	s.Idf.seg.Logger = s.seg.Logger
+	return s.Idf.LoadDict(fileName...)
+}
+
+// LoadIdfStr load and create a new Idf dictionary from the string.
+func (s *Summarizer) LoadIdfStr(str string) error {
+	s.Idf = NewIdf()
+	s.Idf.seg.Logger = s.seg.Logger
+	return s.Idf.LoadDictStr(str)
+}
+
+// vector the term frequency of the sentence words, not the stop words
+func (s *Summarizer) vector(sentence string) map[string]float64 {
+	vec := make(map[string]float64)
+	for _, w := range s.seg.Cut(sentence, true) {
+		w = strings.ToLower(strings.TrimSpace(w))
+		if isWord(w) && !s.isStop(w) {
+			vec[w]++
+		}
+	}
+
+	if s.Idf != nil {
+		for w, tf := range vec {
+			idf, _, ok := s.Idf.Freq(w)
+			if !ok || idf <= 0 {
+				idf = s.Idf.Median()
+			}
+			vec[w] = tf * idf
+		}
+	}
+
+	return vec
+}
+
+// similarity the TF-IDF cosine or the token overlap of the TextRank paper:
+// |Si ∩ Sj| / (log(1 + |Si|) + log(1 + |Sj|))
+func (s *Summarizer) similarity(a, b map[string]float64) float64 {
+	if s.Idf != nil {
+		dot, na, nb := 0.0, 0.0, 0.0
+		for w, v := range a {
+			dot += v * b[w]
+			na += v * v
+		}
+		for _, v := range b {
+			nb += v * v
+		}
+
+		if na == 0 || nb == 0 {
+			return 0
+		}
+		return dot / math.Sqrt(na*nb)
+	}
+
+	common := 0.0
+	for w := range a {
+		if _, ok := b[w]; ok {
+			common++
+		}
+	}
+	if common == 0 {
+		return 0
+	}
+
+	return common / (math.Log(1+float64(len(a))) + math.Log(1+float64(len(b))))
+}
+
+// Summarize return the top sentences of the text in the original order,
+// within the sentences number and the length budget
+func (s *Summarizer) Summarize(text string, opts ...SummaryOptions) []gse.Sentence {
+	var opt SummaryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if opt.Sentences <= 0 {
+		opt.Sentences = 3
+	}
+
+	sentences := s.seg.Sentences(text)
+	vecs := make([]map[string]float64, len(sentences))
+	for i, sen := range sentences {
+		vecs[i] = s.vector(sen.Text)
+	}
+
+	g := NewGraph()
+	for i := range vecs {
+		for j := i + 1; j < len(vecs); j++ {
+			if w := s.similarity(vecs[i], vecs[j]); w > 0 {
+				g.AddEdge(strconv.Itoa(i), strconv.Itoa(j), w)
+			}
+		}
+	}
+
+	scores := make([]float64, len(sentences))
+	for _, r := range g.Rank(opt.TextRank) {
+		i, _ := strconv.Atoi(r.Text)
+		scores[i] = r.Weight
+	}
+
+	order := make([]int, len(sentences))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+
+	var (
+		picked []int
+		length int
+	)
+	for _, i := range order {
+		if len(picked) >= opt.Sentences {
+			break
+		}
+
+		n := utf8.RuneCountInString(sentences[i].Text)
+		if opt.MaxLen > 0 && length+n > opt.MaxLen {
+			continue
+		}
+
+		picked = append(picked, i)
+		length += n
+	}
+	sort.Ints(picked)
+
+	result := make([]gse.Sentence, len(picked))
+	for k, i := range picked {
+		result[k] = sentences[i]
+	}
+
+	return result
+}
+
+// Summary return the summary text of the text, see Summarize
+func (s *Summarizer) Summary(text string, opts ...SummaryOptions) string {
+	var b strings.Builder
+	for i, sen := range s.Summarize(text, opts...) {
+		if i > 0 && sen.Text[0] < utf8.RuneSelf {
+			b.WriteByte(' ')
+		}
+		b.WriteString(sen.Text)
+	}
+
+	return b.String()
+}
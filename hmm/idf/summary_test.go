@@ -0,0 +1,43 @@
+package idf
+
+import (
+	"testing"
+
+	"github.com/vcaesar/tt"
+)
+
+const summaryText = "The cat sat on the mat. Dogs chase the cat around the garden. " +
+	"The cat and the dog are friends now. It rained yesterday. " +
+	"The garden cat likes the dog."
+
+func TestSummarize(t *testing.T) {
+	var s Summarizer
+	s.WithGse(newKeywordSeg())
+
+	ss := s.Summarize(summaryText, SummaryOptions{Sentences: 2})
+	tt.Equal(t, 2, len(ss))
+	tt.True(t, ss[0].Start < ss[1].Start)
+	for _, sen := range ss {
+		tt.Equal(t, sen.Text, summaryText[sen.Start:sen.End])
+		tt.NotEqual(t, "It rained yesterday.", sen.Text)
+	}
+
+	ss = s.Summarize(summaryText, SummaryOptions{Sentences: 5, MaxLen: 60})
+	length := 0
+	for _, sen := range ss {
+		length += len(sen.Text)
+	}
+	tt.True(t, length <= 60)
+	tt.True(t, len(ss) >= 2)
+
+	tt.Equal(t, "It rained yesterday.", s.Summary("It rained yesterday."))
+
+	err := s.LoadIdfStr("cat 1\ngarden 8\ndog 6\ndogs 6\nfriends 9\nmat 9")
+	tt.Nil(t, err)
+	ss = s.Summarize(summaryText, SummaryOptions{Sentences: 1})
+	tt.Equal(t, 1, len(ss))
+	tt.Equal(t, "The garden cat likes the dog.", ss[0].Text)
+
+	tt.Equal(t, "The garden cat likes the dog.",
+		s.Summary(summaryText, SummaryOptions{Sentences: 1}))
+}
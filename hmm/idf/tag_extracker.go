@@ -23,6 +23,18 @@ func (t *TagExtracter) WithGse(segs gse.Segmenter) {
 	t.seg = segs
 }
 
+// WithLogger set the logger of the gse segmenter,
+// the Idf and StopWord dictionary will use it too
+func (t *TagExtracter) WithLogger(logger gse.Logger) {
+	t.seg.Logger = logger
+	if t.Idf != nil {
+		t.Idf.seg.Logger = logger
+	}
+	if t.stopWord != nil {
+		t.stopWord.seg.Logger = logger
+	}
+}
+
 // LoadDict load and create a new dictionary from the file
 func (t *TagExtracter) LoadDict(fileName ...string) error {
 	t.stopWord = NewStopWord()
@@ -32,22 +44,26 @@ func (t *TagExtracter) LoadDict(fileName ...string) error {
 // LoadIdf load and create a new Idf dictionary from the file.
 func (t *TagExtracter) LoadIdf(fileName ...string) error {
 	t.Idf = NewIdf()
+	t.Idf.seg.Logger = t.seg.Logger
 	return t.Idf.LoadDict(fileName...)
 }
 
 // LoadIdfStr load and create a new Idf dictionary from the string.
 func (t *TagExtracter) LoadIdfStr(str string) error {
 	t.Idf = NewIdf()
-	return t.Idf.seg.LoadDictStr(str)
+	t.Idf.seg.Logger = t.seg.Logger
+	return t.Idf.LoadDictStr(str)
 }
 
 // LoadStopWords load and create a new StopWord dictionary from the file.
 func (t *TagExtracter) LoadStopWords(fileName ...string) error {
 	t.stopWord = NewStopWord()
+	t.stopWord.seg.Logger = t.seg.Logger
 	return t.stopWord.LoadDict(fileName...)
 }
 
-// ExtractTags extract the topK key words from text.
+// ExtractTags extract the topK key words from text, the weight is
+// the term frequency * IDF, the Idf median for the unknown words.
 func (t *TagExtracter) ExtractTags(text string, topK int) (tags segment.Segments) {
 	freqMap := make(map[string]float64)
 
@@ -79,7 +95,7 @@ func (t *TagExtracter) ExtractTags(text string, topK int) (tags segment.Segments
 	ws := make(segment.Segments, 0)
 	var s segment.Segment
 	for k, v := range freqMap {
-		if freq, _, ok := t.Idf.Freq(k); ok {
+		if freq, _, ok := t.Idf.Freq(k); ok && freq > 0 {
 			s = segment.Segment{Text: k, Weight: freq * v}
 		} else {
 			s = segment.Segment{Text: k, Weight: t.Idf.median * v}
@@ -1,8 +1,12 @@
 package idf
 
 import (
+	"bufio"
+	"fmt"
+	"io"
 	"math"
 	"sort"
+	"unicode/utf8"
 
 	"github.com/go-ego/gse"
 	"github.com/go-ego/gse/hmm/pos"
@@ -26,67 +30,148 @@ func (t *TextRanker) WithGse(segs gse.Segmenter) {
 	t.seg.WithGse(segs)
 }
 
+// WithLogger set the logger of the gse segmenter
+func (t *TextRanker) WithLogger(logger gse.Logger) {
+	t.seg.WithLogger(logger)
+}
+
 // LoadDict load and create a new dictionary from the file for Textranker
 func (t *TextRanker) LoadDict(fileName ...string) error {
 	// t.seg = new(pos.Segmenter)
 	return t.seg.LoadDict(fileName...)
 }
 
-type edge struct {
-	start, end string
-	weight     float64
+// TextRankOptions the options of the TextRank
+type TextRankOptions struct {
+	// Window the co-occurrence window size, default is 5
+	Window int
+	// Damping the damping factor, default is 0.85
+	Damping float64
+	// Tolerance stop the iteration when the max rank change
+	// is less than it, default is 0 (run all the iterations)
+	Tolerance float64
+	// MaxIter the maximum number of the iterations, default is 10
+	MaxIter int
+
+	// AllowPOS the POS of the words in the graph, default is defaultAllowPOS
+	AllowPOS []string
+	// DenyPOS the POS of the words not in the graph
+	DenyPOS []string
+	// MinLen the minimum word length in runes
+	MinLen int
+	// Distance weight the edge by 1 / distance of the words instead of 1
+	Distance bool
 }
 
-type edges []edge
+func (opt *TextRankOptions) init() {
+	if opt.Window <= 1 {
+		opt.Window = 5
+	}
+	if opt.Damping <= 0 || opt.Damping >= 1 {
+		opt.Damping = dampingFactor
+	}
+	if opt.MaxIter <= 0 {
+		opt.MaxIter = 10
+	}
+	if opt.AllowPOS == nil {
+		opt.AllowPOS = defaultAllowPOS
+	}
+}
+
+// Edge the weighted edge of the TextRank graph
+type Edge struct {
+	Start, End string
+	Weight     float64
+}
+
+type edges []Edge
 
 func (es edges) Len() int {
 	return len(es)
 }
 
 func (es edges) Less(i, j int) bool {
-	return es[i].weight < es[j].weight
+	return es[i].Weight < es[j].Weight
 }
 
 func (es edges) Swap(i, j int) {
 	es[i], es[j] = es[j], es[i]
 }
 
-type undirectWeightedGraph struct {
+// Graph the undirected weighted co-occurrence graph of the TextRank,
+// it can be built by several documents and ranked
+type Graph struct {
 	graph map[string]edges
 	keys  sort.StringSlice
 }
 
-func newUndirectWeightedGraph() *undirectWeightedGraph {
-	u := new(undirectWeightedGraph)
+// NewGraph create a new empty graph
+func NewGraph() *Graph {
+	u := new(Graph)
 	u.graph = make(map[string]edges)
 	u.keys = make(sort.StringSlice, 0)
 	return u
 }
 
-func (u *undirectWeightedGraph) addEdge(start, end string, weight float64) {
+// AddEdge add the undirected edge to the graph
+func (u *Graph) AddEdge(start, end string, weight float64) {
 	// # use a tuple (start, end, weight) instead of a Edge object
 	if _, ok := u.graph[start]; !ok {
 		u.keys = append(u.keys, start)
-		u.graph[start] = edges{edge{start: start, end: end, weight: weight}}
+		u.graph[start] = edges{Edge{Start: start, End: end, Weight: weight}}
 	} else {
 		u.graph[start] = append(u.graph[start],
-			edge{start: start, end: end, weight: weight})
+			Edge{Start: start, End: end, Weight: weight})
 	}
 
 	if _, ok := u.graph[end]; !ok {
 		u.keys = append(u.keys, end)
-		u.graph[end] = edges{edge{start: end, end: start, weight: weight}}
+		u.graph[end] = edges{Edge{Start: end, End: start, Weight: weight}}
 		return
 	}
 	u.graph[end] = append(u.graph[end],
-		edge{start: end, end: start, weight: weight})
+		Edge{Start: end, End: start, Weight: weight})
 }
 
-func (u *undirectWeightedGraph) rank() segment.Segments {
+// Nodes return the words of the graph sorted
+func (u *Graph) Nodes() []string {
 	if !sort.IsSorted(u.keys) {
 		sort.Sort(u.keys)
 	}
 
+	return u.keys
+}
+
+// Edges return the edges of the word
+func (u *Graph) Edges(node string) []Edge {
+	return u.graph[node]
+}
+
+// WriteDot write the graph in the Graphviz DOT format for visualisation
+func (u *Graph) WriteDot(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("graph textrank {\n")
+	for _, n := range u.Nodes() {
+		for _, e := range u.graph[n] {
+			if e.Start < e.End {
+				fmt.Fprintf(bw, "\t%q -- %q [weight=%g];\n", e.Start, e.End, e.Weight)
+			}
+		}
+	}
+	bw.WriteString("}\n")
+
+	return bw.Flush()
+}
+
+// Rank rank the words of the graph, return the normalized weights
+func (u *Graph) Rank(opts ...TextRankOptions) segment.Segments {
+	var opt TextRankOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	opt.init()
+	u.Nodes()
+
 	ws := make(map[string]float64)
 	outSum := make(map[string]float64)
 
@@ -99,30 +184,35 @@ func (u *undirectWeightedGraph) rank() segment.Segments {
 		ws[n] = wsDef
 		sum := 0.0
 		for _, e := range out {
-			sum += e.weight
+			sum += e.Weight
 		}
 		outSum[n] = sum
 	}
 
-	for x := 0; x < 10; x++ {
+	for x := 0; x < opt.MaxIter; x++ {
+		diff := 0.0
 		for _, n := range u.keys {
 			s := 0.0
 			inedges := u.graph[n]
 			for _, e := range inedges {
-				s += e.weight / outSum[e.end] * ws[e.end]
+				s += e.Weight / outSum[e.End] * ws[e.End]
 			}
-			ws[n] = (1 - dampingFactor) + dampingFactor*s
+
+			w := (1 - opt.Damping) + opt.Damping*s
+			diff = math.Max(diff, math.Abs(w-ws[n]))
+			ws[n] = w
+		}
+
+		if diff < opt.Tolerance {
+			break
 		}
 	}
 
 	minRank := math.MaxFloat64
 	maxRank := math.SmallestNonzeroFloat64
 	for _, w := range ws {
-		if w < minRank {
-			minRank = w
-		} else if w > maxRank {
-			maxRank = w
-		}
+		minRank = math.Min(minRank, w)
+		maxRank = math.Max(maxRank, w)
 	}
 
 	result := make(segment.Segments, 0)
@@ -136,43 +226,58 @@ func (u *undirectWeightedGraph) rank() segment.Segments {
 	return result
 }
 
-// TextRankWithPOS extracts keywords from text using TextRank algorithm.
-// Parameter allowPOS allows a []string pos list.
-func (t *TextRanker) TextRankWithPOS(text string, topK int, allowPOS []string) segment.Segments {
-	posFilt := make(map[string]int)
-	for _, pos1 := range allowPOS {
-		posFilt[pos1] = 1
+// AddGraph add the co-occurrence words of the text to the graph
+func (t *TextRanker) AddGraph(g *Graph, text string, opts ...TextRankOptions) {
+	var opt TextRankOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	opt.init()
+
+	allow := toSet(opt.AllowPOS)
+	deny := toSet(opt.DenyPOS)
+	filter := func(p gse.SegPos) bool {
+		return allow[p.Pos] && !deny[p.Pos] &&
+			utf8.RuneCountInString(p.Text) >= opt.MinLen
 	}
 
-	g := newUndirectWeightedGraph()
 	cm := make(map[[2]string]float64)
-	span := 5
+	pairs := t.seg.Cut(text, true)
+	for i := range pairs {
+		if !filter(pairs[i]) {
+			continue
+		}
 
-	var pairs []gse.SegPos
-	pairs = append(pairs, t.seg.Cut(text, true)...)
+		for j := i + 1; j < i+opt.Window && j < len(pairs); j++ {
+			if !filter(pairs[j]) {
+				continue
+			}
 
-	for i := range pairs {
-		_, ok := posFilt[pairs[i].Pos]
-		if ok {
-			for j := i + 1; j < i+span && j < len(pairs); j++ {
-				if _, ok := posFilt[pairs[j].Pos]; !ok {
-					continue
-				}
-
-				if _, ok := cm[[2]string{pairs[i].Text, pairs[j].Text}]; !ok {
-					cm[[2]string{pairs[i].Text, pairs[j].Text}] = 1.0
-				} else {
-					cm[[2]string{pairs[i].Text, pairs[j].Text}] += 1.0
-				}
+			weight := 1.0
+			if opt.Distance {
+				weight /= float64(j - i)
 			}
+			cm[[2]string{pairs[i].Text, pairs[j].Text}] += weight
 		}
 	}
 
 	for startEnd, weight := range cm {
-		g.addEdge(startEnd[0], startEnd[1], weight)
+		g.AddEdge(startEnd[0], startEnd[1], weight)
 	}
+}
+
+// Graph build the TextRank graph of the text
+func (t *TextRanker) Graph(text string, opts ...TextRankOptions) *Graph {
+	g := NewGraph()
+	t.AddGraph(g, text, opts...)
+	return g
+}
 
-	tags := g.rank()
+// TextRankWithOptions extracts keywords from text using TextRank algorithm
+// with the options.
+func (t *TextRanker) TextRankWithOptions(text string, topK int,
+	opt TextRankOptions) segment.Segments {
+	tags := t.Graph(text, opt).Rank(opt)
 	if topK > 0 && len(tags) > topK {
 		tags = tags[:topK]
 	}
@@ -180,6 +285,12 @@ func (t *TextRanker) TextRankWithPOS(text string, topK int, allowPOS []string) s
 	return tags
 }
 
+// TextRankWithPOS extracts keywords from text using TextRank algorithm.
+// Parameter allowPOS allows a []string pos list.
+func (t *TextRanker) TextRankWithPOS(text string, topK int, allowPOS []string) segment.Segments {
+	return t.TextRankWithOptions(text, topK, TextRankOptions{AllowPOS: allowPOS})
+}
+
 // TextRank extract keywords from text using TextRank algorithm.
 // Parameter topK specify how many top keywords to be returned at most.
 func (t *TextRanker) TextRank(text string, topK int) segment.Segments {
@@ -0,0 +1,49 @@
+package idf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/vcaesar/tt"
+)
+
+func TestTextRankOptions(t *testing.T) {
+	var tr TextRanker
+	tr.WithGse(newKeywordSeg())
+
+	text := "机器学习平台的机器学习, 平台学习"
+	tags := tr.TextRank(text, 0)
+	tt.Equal(t, 3, len(tags))
+
+	opt := TextRankOptions{Window: 3, DenyPOS: []string{"vn"}}
+	g := tr.Graph(text, opt)
+	tt.Equal(t, "[平台 机器]", g.Nodes())
+	tt.Equal(t, 0, len(g.Edges("学习")))
+
+	g = tr.Graph(text, TextRankOptions{Window: 3, Distance: true})
+	tt.Equal(t, "[学习 平台 机器]", g.Nodes())
+	w := 0.0
+	for _, e := range g.Edges("机器") {
+		if e.End == "平台" {
+			w += e.Weight
+		}
+	}
+	tt.Equal(t, 1, w)
+
+	tr.AddGraph(g, "机器学习模型")
+	tt.Equal(t, 4, len(g.Nodes()))
+
+	var buf bytes.Buffer
+	err := g.WriteDot(&buf)
+	tt.Nil(t, err)
+	tt.True(t, strings.HasPrefix(buf.String(), "graph textrank {\n"))
+	tt.True(t, strings.Contains(buf.String(), `"学习" -- "机器"`))
+
+	tags = g.Rank(TextRankOptions{MaxIter: 100, Tolerance: 1e-6})
+	tt.Equal(t, 4, len(tags))
+	tt.Equal(t, "学习", tags[0].Text)
+
+	tags = tr.TextRankWithOptions(text, 1, TextRankOptions{MinLen: 3})
+	tt.Equal(t, 0, len(tags))
+}
@@ -0,0 +1,297 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package idf
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-ego/gse/hmm/segment"
+)
+
+// the sentence delimiters of the Yake
+const sentenceEnds = "。！？!?.;；\n"
+
+// Yake the YAKE (Yet Another Keyword Extractor) extractor,
+// the words are scored by the casing, position, frequency,
+// context and sentence features of the single text, it needs no corpus.
+//
+// The YAKE score is lower better, the Weight of the result
+// is 1 / (1 + score) to be sorted the same as the other extractors.
+type Yake struct {
+	extractor
+	// MaxWords the maximum n-gram size of the keywords, default is 3
+	MaxWords int
+	// Window the context window size, default is 1
+	Window int
+}
+
+type yakeTerm struct {
+	tf, upper, acronym float64
+	sentences          []int
+	left, right        map[string]int
+	leftN, rightN      int
+	stop               bool
+
+	score float64
+}
+
+func isSentenceEnd(w string) bool {
+	return !isWord(w) && strings.ContainsAny(w, sentenceEnds)
+}
+
+func isAcronym(w string) bool {
+	if utf8.RuneCountInString(w) < 2 {
+		return false
+	}
+
+	for _, r := range w {
+		if !unicode.IsUpper(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func median(list []int) float64 {
+	s := append([]int(nil), list...)
+	sort.Ints(s)
+	n := len(s)
+	if n%2 == 1 {
+		return float64(s[n/2])
+	}
+	return float64(s[n/2-1]+s[n/2]) / 2
+}
+
+// chunks split the words to the sentences and the chunks
+// delimited by the punctuations, return the chunks of each sentence
+func (y *Yake) chunks(words []string) (sentences [][][]int) {
+	var (
+		sentence [][]int
+		chunk    []int
+	)
+
+	for i, w := range words {
+		if strings.TrimSpace(w) == "" {
+			continue
+		}
+
+		if isWord(w) {
+			chunk = append(chunk, i)
+			continue
+		}
+
+		if len(chunk) > 0 {
+			sentence = append(sentence, chunk)
+			chunk = nil
+		}
+		if isSentenceEnd(w) && len(sentence) > 0 {
+			sentences = append(sentences, sentence)
+			sentence = nil
+		}
+	}
+
+	if len(chunk) > 0 {
+		sentence = append(sentence, chunk)
+	}
+	if len(sentence) > 0 {
+		sentences = append(sentences, sentence)
+	}
+
+	return
+}
+
+// terms calculate the features and the score of each word
+func (y *Yake) terms(words, origin []string, sentences [][][]int,
+	window int) map[string]*yakeTerm {
+	terms := make(map[string]*yakeTerm)
+	term := func(w string) *yakeTerm {
+		t, ok := terms[w]
+		if !ok {
+			t = &yakeTerm{left: make(map[string]int), right: make(map[string]int)}
+			t.stop = y.isStop(w)
+			terms[w] = t
+		}
+		return t
+	}
+
+	for s, sentence := range sentences {
+		var seq []string
+		for c, chunk := range sentence {
+			for k, i := range chunk {
+				w := strings.ToLower(words[i])
+				t := term(w)
+				t.tf++
+				t.sentences = append(t.sentences, s)
+
+				r, _ := utf8.DecodeRuneInString(origin[i])
+				if isAcronym(origin[i]) {
+					t.acronym++
+				} else if unicode.IsUpper(r) && (c > 0 || k > 0) {
+					// not the beginning of the sentence
+					t.upper++
+				}
+
+				seq = append(seq, w)
+			}
+		}
+
+		for i, w := range seq {
+			t := terms[w]
+			for j := i - window; j < i; j++ {
+				if j >= 0 {
+					t.left[seq[j]]++
+					t.leftN++
+				}
+			}
+			for j := i + 1; j <= i+window && j < len(seq); j++ {
+				t.right[seq[j]]++
+				t.rightN++
+			}
+		}
+	}
+
+	var tfs []float64
+	maxTF := 0.0
+	for _, t := range terms {
+		if !t.stop {
+			tfs = append(tfs, t.tf)
+			maxTF = math.Max(maxTF, t.tf)
+		}
+	}
+
+	mean, std := 0.0, 0.0
+	for _, tf := range tfs {
+		mean += tf
+	}
+	if len(tfs) > 0 {
+		mean /= float64(len(tfs))
+	}
+	for _, tf := range tfs {
+		std += (tf - mean) * (tf - mean)
+	}
+	if len(tfs) > 0 {
+		std = math.Sqrt(std / float64(len(tfs)))
+	}
+
+	for _, t := range terms {
+		if t.stop {
+			continue
+		}
+
+		sentSet := make(map[int]bool)
+		for _, s := range t.sentences {
+			sentSet[s] = true
+		}
+
+		tCase := math.Max(t.upper, t.acronym) / (1 + math.Log(t.tf))
+		tPos := math.Log(math.Log(3 + median(t.sentences)))
+		tFreq := t.tf / (mean + std)
+
+		wl, wr := 0.0, 0.0
+		if t.leftN > 0 {
+			wl = float64(len(t.left)) / float64(t.leftN)
+		}
+		if t.rightN > 0 {
+			wr = float64(len(t.right)) / float64(t.rightN)
+		}
+		tRel := 1 + (wl+wr)*t.tf/maxTF
+		tSent := float64(len(sentSet)) / float64(len(sentences))
+
+		t.score = tRel * tPos / (tCase + tFreq/tRel + tSent/tRel)
+	}
+
+	return terms
+}
+
+// ExtractTags extract the topK keywords from text
+func (y *Yake) ExtractTags(text string, topK int) segment.Segments {
+	maxWords, window := y.MaxWords, y.Window
+	if maxWords <= 0 {
+		maxWords = 3
+	}
+	if window <= 0 {
+		window = 1
+	}
+
+	words, origin := y.words(text)
+	sentences := y.chunks(words)
+	terms := y.terms(words, origin, sentences, window)
+
+	type candidate struct {
+		words []string
+		tf    float64
+	}
+	cands := make(map[string]*candidate)
+
+	for _, sentence := range sentences {
+		for _, chunk := range sentence {
+			for i := range chunk {
+				for n := 1; n <= maxWords && i+n <= len(chunk); n++ {
+					gram := make([]string, n)
+					for k := range gram {
+						gram[k] = strings.ToLower(words[chunk[i+k]])
+					}
+
+					// the keyword can not begin or end with the stop word
+					if terms[gram[0]].stop || terms[gram[n-1]].stop {
+						continue
+					}
+
+					key := phraseText(gram)
+					if utf8.RuneCountInString(key) < 2 {
+						continue
+					}
+
+					c, ok := cands[key]
+					if !ok {
+						c = &candidate{words: gram}
+						cands[key] = c
+					}
+					c.tf++
+				}
+			}
+		}
+	}
+
+	ws := make(segment.Segments, 0, len(cands))
+	for key, c := range cands {
+		prod, sum := 1.0, 0.0
+		for i, w := range c.words {
+			t := terms[w]
+			if !t.stop {
+				prod *= t.score
+				sum += t.score
+				continue
+			}
+
+			// the stop word in the middle is scored by the probability
+			// of the adjacent words, the low probability is penalized
+			prev, next := terms[c.words[i-1]], terms[c.words[i+1]]
+			p := float64(prev.right[w]) / prev.tf *
+				float64(t.right[c.words[i+1]]) / next.tf
+			prod *= 2 - p
+			sum -= 1 - p
+		}
+
+		score := prod / (c.tf * (1 + sum))
+		ws = append(ws, segment.Segment{Text: key, Weight: 1 / (1 + score)})
+	}
+
+	return sortSegments(ws, topK)
+}
@@ -51,13 +51,12 @@ func (d *Dict) Freq(key string) (float64, string, bool) {
 
 // Pos find the key return the POS and existence
 func (d *Dict) Pos(key string) (string, bool) {
-	value, _, _ := d.Seg.Value(key)
-	if value == 0 {
+	token, ok := d.Seg.FindToken(key)
+	if !ok {
 		return "", false
 	}
 
-	pos := d.Seg.Dict.Tokens[value].Pos()
-	return pos, true
+	return token.Pos(), true
 }
 
 func (d *Dict) loadDict(files ...string) error {
@@ -50,6 +50,11 @@ func (seg *Segmenter) WithGse(segs gse.Segmenter) {
 	seg.dict.Seg = segs
 }
 
+// WithLogger set the logger of the gse segmenter
+func (seg *Segmenter) WithLogger(logger gse.Logger) {
+	seg.dict.Seg.Logger = logger
+}
+
 // LoadDict load dictionary from the file.
 func (seg *Segmenter) LoadDict(fileName ...string) error {
 	return seg.dict.loadDict(fileName...)
@@ -0,0 +1,113 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package fileio is the shared file IO of the dictionary and the models,
+// the model file is a magic line, a JSON header line and the little
+// endian float32 parameters
+package fileio
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrMagic the magic line is not the expected one
+var ErrMagic = errors.New("fileio: not the expected model file")
+
+// CountWriter count the bytes written to the W
+type CountWriter struct {
+	W io.Writer
+	N int64
+}
+
+func (cw *CountWriter) Write(p []byte) (int, error) {
+	n, err := cw.W.Write(p)
+	cw.N += int64(n)
+	return n, err
+}
+
+// ReadHeader read the magic line and the JSON header line to h,
+// return the header line and the reader of the parameters
+func ReadHeader(r io.Reader, magic string, h interface{}) (string, *bufio.Reader, error) {
+	br := bufio.NewReader(r)
+	line, err := br.ReadString('\n')
+	if err != nil {
+		return "", nil, err
+	}
+	if strings.TrimSpace(line) != magic {
+		return "", nil, ErrMagic
+	}
+
+	line, err = br.ReadString('\n')
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := json.Unmarshal([]byte(line), h); err != nil {
+		return "", nil, err
+	}
+	return line, br, nil
+}
+
+// ReadParams read the parameters in order
+func ReadParams(r io.Reader, params [][]float32) error {
+	for _, p := range params {
+		if err := binary.Read(r, binary.LittleEndian, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteModel write the magic line, the JSON header line
+// and the parameters to w, return the number of bytes written
+func WriteModel(w io.Writer, magic string, h interface{}, params [][]float32) (int64, error) {
+	head, err := json.Marshal(h)
+	if err != nil {
+		return 0, err
+	}
+
+	cw := &CountWriter{W: w}
+	bw := bufio.NewWriter(cw)
+	bw.WriteString(magic + "\n")
+	bw.Write(head)
+	bw.WriteString("\n")
+	for _, p := range params {
+		if err := binary.Write(bw, binary.LittleEndian, p); err != nil {
+			return cw.N, err
+		}
+	}
+
+	err = bw.Flush()
+	return cw.N, err
+}
+
+// Save write the w to the file
+func Save(file string, w io.WriterTo) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.WriteTo(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,58 @@
+package fileio
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vcaesar/tt"
+)
+
+type header struct {
+	Size int `json:"size"`
+}
+
+func TestModel(t *testing.T) {
+	params := [][]float32{{1, 2}, {-0.5}}
+
+	var buf bytes.Buffer
+	n, err := WriteModel(&buf, "test 1", header{Size: 3}, params)
+	tt.Nil(t, err)
+	tt.Equal(t, buf.Len(), n)
+	tt.True(t, strings.HasPrefix(buf.String(), "test 1\n{\"size\":3}\n"))
+
+	var h header
+	line, br, err := ReadHeader(&buf, "test 1", &h)
+	tt.Nil(t, err)
+	tt.Equal(t, "{\"size\":3}\n", line)
+	tt.Equal(t, 3, h.Size)
+
+	got := [][]float32{make([]float32, 2), make([]float32, 1)}
+	tt.Nil(t, ReadParams(br, got))
+	tt.Equal(t, params, got)
+	tt.NotNil(t, ReadParams(br, got))
+
+	_, _, err = ReadHeader(strings.NewReader("other 1\n{}\n"), "test 1", &h)
+	tt.Equal(t, ErrMagic, err)
+	_, _, err = ReadHeader(strings.NewReader("test 1\n{\n"), "test 1", &h)
+	tt.NotNil(t, err)
+}
+
+type writerTo []float32
+
+func (w writerTo) WriteTo(out io.Writer) (int64, error) {
+	return WriteModel(out, "test 1", header{Size: len(w)}, [][]float32{w})
+}
+
+func TestSave(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "model")
+	tt.Nil(t, Save(file, writerTo{1, 2}))
+	tt.NotNil(t, Save(filepath.Join(file, "dir", "model"), writerTo{1}))
+
+	var buf bytes.Buffer
+	cw := &CountWriter{W: &buf}
+	cw.Write([]byte("abc"))
+	tt.Equal(t, 3, cw.N)
+}
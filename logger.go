@@ -0,0 +1,94 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package gse
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Logger is the structured logger used by the segmenter,
+// args are alternating key and value pairs.
+//
+// The *slog.Logger satisfies this interface, so it can be used directly:
+//
+//	seg.Logger = slog.Default()
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// stdLogger writes to the standard log package,
+// it is used when the Segmenter.Logger is nil.
+//
+// Info level is skipped when skip is true (SkipLog),
+// debug level is only printed when more is true (MoreLog).
+type stdLogger struct {
+	skip, more bool
+}
+
+func (l stdLogger) print(level, msg string, args ...interface{}) {
+	var b strings.Builder
+	b.WriteString(level)
+	b.WriteString(" ")
+	b.WriteString(msg)
+
+	for i := 0; i < len(args); i += 2 {
+		if i+1 < len(args) {
+			fmt.Fprintf(&b, " %v=%v", args[i], args[i+1])
+		} else {
+			fmt.Fprintf(&b, " %v", args[i])
+		}
+	}
+
+	log.Println(b.String())
+}
+
+// Debug print the debug log
+func (l stdLogger) Debug(msg string, args ...interface{}) {
+	if l.more {
+		l.print("DEBUG", msg, args...)
+	}
+}
+
+// Info print the info log
+func (l stdLogger) Info(msg string, args ...interface{}) {
+	if !l.skip {
+		l.print("INFO", msg, args...)
+	}
+}
+
+// Warn print the warn log
+func (l stdLogger) Warn(msg string, args ...interface{}) {
+	l.print("WARN", msg, args...)
+}
+
+// Error print the error log
+func (l stdLogger) Error(msg string, args ...interface{}) {
+	l.print("ERROR", msg, args...)
+}
+
+// Log return the segmenter logger,
+// use the standard log package if not set the Logger
+func (seg *Segmenter) Log() Logger {
+	if seg.Logger != nil {
+		return seg.Logger
+	}
+
+	return stdLogger{skip: seg.SkipLog, more: seg.MoreLog}
+}
@@ -0,0 +1,45 @@
+package gse
+
+import (
+	"testing"
+
+	"github.com/vcaesar/tt"
+)
+
+type testLogger struct {
+	msgs []string
+	args [][]interface{}
+}
+
+func (l *testLogger) log(msg string, args ...interface{}) {
+	l.msgs = append(l.msgs, msg)
+	l.args = append(l.args, args)
+}
+
+func (l *testLogger) Debug(msg string, args ...interface{}) { l.log(msg, args...) }
+func (l *testLogger) Info(msg string, args ...interface{})  { l.log(msg, args...) }
+func (l *testLogger) Warn(msg string, args ...interface{})  { l.log(msg, args...) }
+func (l *testLogger) Error(msg string, args ...interface{}) { l.log(msg, args...) }
+
+func TestLogger(t *testing.T) {
+	var seg1 Segmenter
+	tt.Equal(t, stdLogger{}, seg1.Log())
+
+	lg := &testLogger{}
+	seg1.Logger = lg
+	err := seg1.LoadDict("testdata/zh/test_dict1.txt")
+	tt.Nil(t, err)
+
+	n := len(lg.msgs)
+	tt.Equal(t, "gse dict files path", lg.msgs[0])
+	tt.Equal(t, "load the gse dictionary", lg.msgs[1])
+	tt.Equal(t, "[file testdata/zh/test_dict1.txt format gse]", lg.args[1])
+	tt.Equal(t, "gse dictionary file loaded", lg.msgs[n-2])
+	tt.Equal(t, "gse dictionary loaded finished", lg.msgs[n-1])
+	tt.Equal(t, "tokens", lg.args[n-1][0])
+	tt.Equal(t, seg1.Dict.NumTokens(), lg.args[n-1][1])
+
+	err = seg1.LoadStop("testdata/not_exist.txt")
+	tt.NotNil(t, err)
+	tt.Equal(t, "could not load dictionaries", lg.msgs[len(lg.msgs)-1])
+}
@@ -0,0 +1,429 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+/*
+Package ner is the named entity recognition of the persons, places and
+organizations, it merges the "nr", "ns" and "nt" pieces of the POS tagger
+by the surname table and the place and organization suffix rules:
+
+	var r ner.Recognizer
+	r.LoadDict()
+	entities := r.Recognize("王小明在北京大学读书")
+*/
+package ner
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-ego/gse"
+	"github.com/go-ego/gse/hmm/pos"
+)
+
+// The entity types
+const (
+	Person = "PER"
+	Place  = "LOC"
+	Org    = "ORG"
+)
+
+var (
+	// DefaultSurnames the default common Chinese surnames
+	DefaultSurnames = strings.Fields(`王 李 张 刘 陈 杨 黄 赵 吴 周 徐 孙 马 朱
+		胡 郭 何 高 林 罗 郑 梁 谢 宋 唐 许 韩 冯 邓 曹 彭 曾 肖 田 董 袁 潘 于 蒋
+		蔡 余 杜 叶 程 苏 魏 吕 丁 任 沈 姚 卢 姜 崔 钟 谭 陆 汪 范 金 石 廖 贾 夏
+		韦 付 方 白 邹 孟 熊 秦 邱 江 尹 薛 闫 段 雷 侯 龙 史 陶 黎 贺 顾 毛 郝 龚
+		邵 万 钱 严 覃 武 戴 莫 孔 向 汤 常 温 康 施 文 牛 樊 葛 邢 安 齐 易 乔 伍
+		庞 颜 倪 庄 聂 章 鲁 岳 翟 殷 詹 申 欧 耿 关 兰 焦 俞 左 柳 甘 祝 包 宁 尚
+		符 舒 阮 柯 纪 梅 童 凌 毕 单 季 裴 霍 涂 成 苗 谷 盛 曲 翁 冉 骆 蓝 路 游
+		辛 靳 管 柴 蒙 鲍 华 喻 祁 蒲 房 滕 屈 饶 解 牟 艾 尤 阳 时 穆 农 司 卓 古
+		吉 缪 简 车 项 连 芦 麦 褚 娄 窦 戚 岑 景 党 宫 费 卜 冷 晏 席 卫 米 柏 宗
+		瞿 桂 全 佟 应 臧 闵 苟 邬 边 卞 姬 师 和 仇 栾 隋 商 刁 沙 荣 巫 寇 桑 郎
+		甄 丛 仲 虞 敖 巩 明 佘 池 查 麻 苑 迟 邝 欧阳 司马 上官 诸葛 东方 皇甫
+		尉迟 公孙 慕容 长孙 宇文 司徒 夏侯 令狐 端木 独孤 南宫 西门`)
+
+	// ambiguous the surnames which are also the high frequency function
+	// or common chars, they need the person tag to start a name
+	ambiguous = toSet(strings.Fields(`和 时 于 成 常 文 明 方 向 安 全 关 应
+		单 解 连 车 路 高 万 包 华 白 石 金 司 古 齐 管 宁 农 费 冷 师 商 沙`))
+
+	// DefaultOrgSuffix the default organization suffixes
+	DefaultOrgSuffix = strings.Fields(`公司 集团 大学 学院 学校 中学 小学 银行
+		医院 研究院 研究所 实验室 委员会 协会 学会 基金会 联合会 中心 政府
+		法院 检察院 公安局 报社 电视台 出版社 俱乐部 事务所 工厂 商会 局 部
+		厅 署 厂 社 党 队 所`)
+
+	// DefaultLocSuffix the default place suffixes
+	DefaultLocSuffix = strings.Fields(`省 市 县 区 镇 乡 村 州 盟 旗 自治区
+		自治州 特别行政区 街道 大街 路 街 巷 岛 山 河 湖 江 湾`)
+)
+
+// Entity the named entity
+type Entity struct {
+	Text string
+	// Type the entity type, Person, Place or Org
+	Type string
+	// Start and End the bytes offsets of the entity in the text
+	Start, End int
+	// Confidence the rule confidence of the entity in [0, 1]
+	Confidence float64
+}
+
+// Recognizer the named entity recognizer
+type Recognizer struct {
+	seg pos.Segmenter
+
+	// MinConfidence drop the entities below it
+	MinConfidence float64
+	// MaxOrgWords the max words before the organization suffix, default is 4
+	MaxOrgWords int
+
+	surnames  map[string]bool
+	orgSuffix []string
+	locSuffix []string
+}
+
+// WithGse register the gse segmenter of the POS tagger
+func (r *Recognizer) WithGse(segs gse.Segmenter) {
+	r.seg.WithGse(segs)
+}
+
+// LoadDict load the dictionary of the POS tagger
+func (r *Recognizer) LoadDict(fileName ...string) error {
+	return r.seg.LoadDict(fileName...)
+}
+
+func (r *Recognizer) init() {
+	if r.surnames == nil {
+		r.surnames = make(map[string]bool, len(DefaultSurnames))
+		for _, s := range DefaultSurnames {
+			r.surnames[s] = true
+		}
+	}
+	if r.orgSuffix == nil {
+		r.orgSuffix = addSuffix(nil, DefaultOrgSuffix)
+	}
+	if r.locSuffix == nil {
+		r.locSuffix = addSuffix(nil, DefaultLocSuffix)
+	}
+	if r.MaxOrgWords <= 0 {
+		r.MaxOrgWords = 4
+	}
+}
+
+// AddSurname add the surnames
+func (r *Recognizer) AddSurname(surnames ...string) {
+	r.init()
+	for _, s := range surnames {
+		r.surnames[s] = true
+	}
+}
+
+// AddOrgSuffix add the organization suffixes
+func (r *Recognizer) AddOrgSuffix(suffix ...string) {
+	r.init()
+	r.orgSuffix = addSuffix(r.orgSuffix, suffix)
+}
+
+// AddLocSuffix add the place suffixes
+func (r *Recognizer) AddLocSuffix(suffix ...string) {
+	r.init()
+	r.locSuffix = addSuffix(r.locSuffix, suffix)
+}
+
+// addSuffix add the suffixes sorted by the length, the longest first
+func addSuffix(list, suffix []string) []string {
+	list = append(append([]string(nil), list...), suffix...)
+	sort.SliceStable(list, func(i, j int) bool {
+		return len(list[i]) > len(list[j])
+	})
+	return list
+}
+
+// IsSurname return true if the text is a surname
+func (r *Recognizer) IsSurname(text string) bool {
+	r.init()
+	return r.surnames[text]
+}
+
+// Recognize recognize the entities of the text by the POS tagger
+func (r *Recognizer) Recognize(text string) []Entity {
+	return r.FromPos(text, r.seg.Cut(text, true))
+}
+
+type token struct {
+	text, pos  string
+	start, end int
+	runes      int
+	used       bool
+}
+
+func isHan(text string) bool {
+	if text == "" {
+		return false
+	}
+	for _, c := range text {
+		if !unicode.Is(unicode.Han, c) {
+			return false
+		}
+	}
+	return true
+}
+
+func isWord(text string) bool {
+	return strings.IndexFunc(text, func(c rune) bool {
+		return unicode.IsLetter(c) || unicode.IsNumber(c)
+	}) >= 0
+}
+
+func hasTag(tag string, tags ...string) bool {
+	for _, t := range tags {
+		if tag == t {
+			return true
+		}
+	}
+	return false
+}
+
+func toSet(list []string) map[string]bool {
+	m := make(map[string]bool, len(list))
+	for _, s := range list {
+		m[s] = true
+	}
+	return m
+}
+
+// unknown the word is tagged the person or unknown by the tagger
+func unknown(tag string) bool {
+	return isPerson(tag) || tag == "x" || tag == ""
+}
+
+func isPerson(tag string) bool {
+	return hasTag(tag, "nr", "nrfg", "nrt")
+}
+
+// orgWord the word can be the part of the organization name
+func orgWord(t token) bool {
+	if t.used || !isWord(t.text) {
+		return false
+	}
+	return hasTag(t.pos, "n", "nz", "ns", "nt", "nr", "nrfg", "nrt",
+		"j", "eng", "vn", "an", "ng", "l", "x")
+}
+
+// givenName the word can be the given name after the surname
+func givenName(t token) bool {
+	return !t.used && isHan(t.text) && t.runes <= 2 &&
+		hasTag(t.pos, "nr", "nrfg", "x", "ng", "n", "a", "ag", "m",
+			"nz", "j", "g", "vg", "tg", "")
+}
+
+func endsWith(text string, suffix []string) string {
+	for _, s := range suffix {
+		if strings.HasSuffix(text, s) {
+			return s
+		}
+	}
+	return ""
+}
+
+// FromPos recognize the entities of the text by the POS words of it,
+// such as the output of the hmm/pos or gse.Segmenter.Pos
+func (r *Recognizer) FromPos(text string, words []gse.SegPos) []Entity {
+	r.init()
+
+	tokens := make([]token, 0, len(words))
+	cur := 0
+	for _, w := range words {
+		// skip the word not in the text
+		i := strings.Index(text[cur:], w.Text)
+		if i < 0 || w.Text == "" {
+			continue
+		}
+		start := cur + i
+		cur = start + len(w.Text)
+
+		tokens = append(tokens, token{text: w.Text, pos: w.Pos,
+			start: start, end: start + len(w.Text),
+			runes: utf8.RuneCountInString(w.Text),
+		})
+	}
+
+	var entities []Entity
+	add := func(typ string, from, to int, conf float64) {
+		for k := from; k <= to; k++ {
+			tokens[k].used = true
+		}
+
+		start, end := tokens[from].start, tokens[to].end
+		entities = append(entities, Entity{Text: text[start:end], Type: typ,
+			Start: start, End: end, Confidence: conf})
+	}
+
+	r.orgs(tokens, add)
+	r.persons(tokens, add)
+	r.places(tokens, add)
+
+	sort.Slice(entities, func(i, j int) bool {
+		return entities[i].Start < entities[j].Start
+	})
+
+	result := entities[:0]
+	for _, e := range entities {
+		if e.Confidence >= r.MinConfidence {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+type addFunc func(typ string, from, to int, conf float64)
+
+// orgs recognize the organizations by the "nt" and the suffix rules
+func (r *Recognizer) orgs(tokens []token, add addFunc) {
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		if t.used {
+			continue
+		}
+
+		// the one char suffix is not accepted in the whole word,
+		// such as "干部", "厕所" and "结局"
+		suffix := endsWith(t.text, r.orgSuffix)
+		whole := suffix != "" && len(t.text) > len(suffix) &&
+			utf8.RuneCountInString(suffix) > 1 &&
+			hasTag(t.pos, "n", "nt", "nz", "x")
+		if suffix == "" || (t.text != suffix && !whole) {
+			if t.pos == "nt" {
+				add(Org, i, i, 0.8)
+			}
+			continue
+		}
+
+		// extend to the left words of the organization name
+		from := i
+		named := false
+		for k := i - 1; k >= 0 && i-k <= r.MaxOrgWords && orgWord(tokens[k]); k-- {
+			from = k
+			named = named || hasTag(tokens[k].pos, "ns", "nt", "nz", "nr", "nrt", "eng", "j")
+		}
+
+		if from == i && !whole {
+			continue
+		}
+
+		conf := 0.75
+		switch {
+		case t.pos == "nt" || named:
+			conf = 0.9
+		case from == i:
+			conf = 0.7
+		case utf8.RuneCountInString(suffix) == 1:
+			conf = 0.6
+		}
+		add(Org, from, i, conf)
+	}
+}
+
+// persons recognize the persons by the "nr" words and the surname table
+func (r *Recognizer) persons(tokens []token, add addFunc) {
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		if t.used {
+			continue
+		}
+
+		surname := r.surnames[t.text]
+		if !surname && isPerson(t.pos) {
+			// the full name with the surname
+			first := string([]rune(t.text)[:1])
+			conf := 0.7
+			if t.runes >= 2 && t.runes <= 4 && (r.surnames[first] ||
+				t.runes > 2 && r.surnames[string([]rune(t.text)[:2])]) {
+				conf = 0.9
+			}
+
+			to := i
+			for to+1 < len(tokens) && isPerson(tokens[to+1].pos) && !tokens[to+1].used {
+				to++
+			}
+			add(Person, i, to, conf)
+			i = to
+			continue
+		}
+
+		if !surname {
+			continue
+		}
+		if i+1 >= len(tokens) || !givenName(tokens[i+1]) {
+			// the surname only, such as "老王"
+			if isPerson(t.pos) {
+				add(Person, i, i, 0.6)
+			}
+			continue
+		}
+
+		// the surname or the given name should be tagged the person
+		// or unknown, such as not "和/c 朋友/n" or "时/ng 间/n"
+		if !unknown(t.pos) && !unknown(tokens[i+1].pos) ||
+			ambiguous[t.text] && !isPerson(t.pos) && !isPerson(tokens[i+1].pos) {
+			continue
+		}
+
+		// the surname and the given name
+		to, conf := i+1, 0.65
+		if isPerson(tokens[to].pos) {
+			conf = 0.9
+		} else if isPerson(t.pos) {
+			conf = 0.8
+		}
+
+		next := to + 1
+		if tokens[to].runes == 1 && next < len(tokens) && givenName(tokens[next]) &&
+			tokens[next].runes == 1 {
+			to = next
+		}
+		add(Person, i, to, conf)
+		i = to
+	}
+}
+
+// places recognize the places by the "ns" words and the suffix rules
+func (r *Recognizer) places(tokens []token, add addFunc) {
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i].used || tokens[i].pos != "ns" {
+			continue
+		}
+
+		to, conf := i, 0.8
+		for to+1 < len(tokens) && !tokens[to+1].used {
+			next := tokens[to+1]
+			if next.pos == "ns" {
+				to++
+				continue
+			}
+
+			if next.text == endsWith(next.text, r.locSuffix) {
+				to++
+				conf = 0.9
+			}
+			break
+		}
+
+		add(Place, i, to, conf)
+		i = to
+	}
+}
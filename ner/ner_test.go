@@ -0,0 +1,108 @@
+package ner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-ego/gse"
+	"github.com/vcaesar/tt"
+)
+
+// parse parse the "word/pos" text to the Pos words
+func parse(text string) (string, []gse.SegPos) {
+	var (
+		b     strings.Builder
+		words []gse.SegPos
+	)
+	for _, w := range strings.Fields(text) {
+		i := strings.LastIndex(w, "/")
+		words = append(words, gse.SegPos{Text: w[:i], Pos: w[i+1:]})
+		b.WriteString(w[:i])
+	}
+	return b.String(), words
+}
+
+func recognize(r *Recognizer, text string) []Entity {
+	return r.FromPos(parse(text))
+}
+
+func TestPerson(t *testing.T) {
+	var r Recognizer
+	tt.True(t, r.IsSurname("欧阳"))
+
+	e := recognize(&r, "王/nr 小明/nr 说/v")
+	tt.Equal(t, "[{王小明 PER 0 9 0.9}]", e)
+
+	e = recognize(&r, "李/x 华/x 来/v 了/ul")
+	tt.Equal(t, "[{李华 PER 0 6 0.65}]", e)
+
+	e = recognize(&r, "欧阳/nr 修/v 和/c 奥巴马/nrt")
+	tt.Equal(t, "[{欧阳 PER 0 6 0.6} {奥巴马 PER 12 21 0.7}]", e)
+
+	e = recognize(&r, "我/r 叫/v 张三丰/nr")
+	tt.Equal(t, "[{张三丰 PER 6 15 0.9}]", e)
+
+	tt.Equal(t, 0, len(recognize(&r, "我/r 和/c 朋友/n 去/v")))
+	tt.Equal(t, 0, len(recognize(&r, "时/ng 间/n 到/v 了/ul")))
+	tt.Equal(t, 0, len(recognize(&r, "和/x 平/x 发展/vn")))
+
+	r.AddSurname("禤")
+	e = recognize(&r, "禤/x 小/a 华/x 到/v")
+	tt.Equal(t, "[{禤小华 PER 0 9 0.65}]", e)
+}
+
+func TestPlaceOrg(t *testing.T) {
+	var r Recognizer
+	e := recognize(&r, "北京/ns 市/n 海淀/ns 区/n 的/uj 清华/nz 大学/n")
+	tt.Equal(t, "[{北京市 LOC 0 9 0.9} {海淀区 LOC 9 18 0.9} {清华大学 ORG 21 33 0.9}]", e)
+
+	e = recognize(&r, "他/r 在/p 中国/ns 工商/n 银行/n 工作/vn")
+	tt.Equal(t, "[{中国工商银行 ORG 6 24 0.9}]", e)
+
+	e = recognize(&r, "阿里巴巴集团/nt 和/c 腾讯公司/n")
+	tt.Equal(t, "[{阿里巴巴集团 ORG 0 18 0.9} {腾讯公司 ORG 21 33 0.7}]", e)
+
+	e = recognize(&r, "内部/f 的/uj 公司/n")
+	tt.Equal(t, 0, len(e))
+
+	e = recognize(&r, "干部/n 去/v 厕所/n 看/v 结局/n")
+	tt.Equal(t, 0, len(e))
+
+	e = recognize(&r, "国家/n 统计/vn 局/n")
+	tt.Equal(t, "[{国家统计局 ORG 0 15 0.6}]", e)
+
+	r.AddOrgSuffix("工作室")
+	e = recognize(&r, "上海/ns 光影/n 工作室/n")
+	tt.Equal(t, "[{上海光影工作室 ORG 0 21 0.9}]", e)
+
+	r.MinConfidence = 0.95
+	tt.Equal(t, 0, len(recognize(&r, "上海/ns 光影/n 工作室/n")))
+}
+
+func TestFromPosUnmatched(t *testing.T) {
+	var r Recognizer
+	tt.Equal(t, 0, len(r.FromPos("ab", []gse.SegPos{{Text: "abc"}})))
+
+	e := r.FromPos("王小明说", []gse.SegPos{{Text: "王小明", Pos: "nr"},
+		{Text: "話", Pos: "v"}, {Text: "说", Pos: "v"}})
+	tt.Equal(t, "[{王小明 PER 0 9 0.9}]", e)
+}
+
+func TestRecognize(t *testing.T) {
+	var seg gse.Segmenter
+	seg.SkipLog = true
+	seg.LoadDictStr(`的 1000 uj
+王 100 nr
+小明 100 nr
+在 1000 p
+北京 100 ns
+大学 100 n
+读书 100 v`)
+
+	var r Recognizer
+	r.WithGse(seg)
+	text := "王小明在北京大学读书"
+	e := r.Recognize(text)
+	tt.Equal(t, "[{王小明 PER 0 9 0.9} {北京大学 ORG 12 24 0.9}]", e)
+	tt.Equal(t, "北京大学", text[e[1].Start:e[1].End])
+}
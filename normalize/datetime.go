@@ -0,0 +1,594 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package normalize
+
+import (
+	"strings"
+	"time"
+)
+
+// The layouts of the normalized date and time
+const (
+	YearLayout  = "2006"
+	MonthLayout = "2006-01"
+	DateLayout  = "2006-01-02"
+	TimeLayout  = "2006-01-02T15:04:05"
+)
+
+type relWord struct {
+	word   string
+	n      int
+	period string
+}
+
+var (
+	relDays = []relWord{
+		{"大后天", 3, ""}, {"大前天", -3, ""}, {"今天", 0, ""}, {"今日", 0, ""},
+		{"明天", 1, ""}, {"明日", 1, ""}, {"后天", 2, ""}, {"昨天", -1, ""},
+		{"昨日", -1, ""}, {"前天", -2, ""}, {"今晚", 0, "晚上"}, {"今早", 0, "早上"},
+		{"明晚", 1, "晚上"}, {"明早", 1, "早上"}, {"昨晚", -1, "晚上"},
+	}
+
+	relYears = []relWord{
+		{"今年", 0, ""}, {"明年", 1, ""}, {"后年", 2, ""}, {"去年", -1, ""}, {"前年", -2, ""},
+	}
+
+	relMonths = []relWord{
+		{"这个月", 0, ""}, {"本月", 0, ""}, {"下个月", 1, ""}, {"下月", 1, ""},
+		{"上个月", -1, ""}, {"上月", -1, ""},
+	}
+
+	relWeeks = []relWord{
+		{"下下个", 2, ""}, {"下下", 2, ""}, {"下个", 1, ""}, {"下", 1, ""},
+		{"上上个", -2, ""}, {"上上", -2, ""}, {"上个", -1, ""}, {"上", -1, ""},
+		{"这个", 0, ""}, {"这", 0, ""}, {"本", 0, ""},
+	}
+
+	weekdays = map[rune]int{
+		'一': 1, '二': 2, '三': 3, '四': 4, '五': 5, '六': 6, '日': 7, '天': 7,
+		'1': 1, '2': 2, '3': 3, '4': 4, '5': 5, '6': 6, '7': 7,
+	}
+
+	// periods the periods of the day, the longest first
+	periods = []string{
+		"凌晨", "早上", "早晨", "清晨", "上午", "中午", "下午", "午后",
+		"傍晚", "晚上", "晚间", "夜里", "夜间", "半夜", "早", "晚",
+	}
+)
+
+func relWordAt(s *scanner, i int, words []relWord) (relWord, int, bool) {
+	for _, w := range words {
+		if _, end, ok := s.word(i, w.word); ok {
+			return w, end, true
+		}
+	}
+	return relWord{}, i, false
+}
+
+// day return the date of the reference location
+func (s *scanner) day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, s.ref.Location())
+}
+
+func (s *scanner) today() time.Time {
+	return s.day(s.ref.Year(), s.ref.Month(), s.ref.Day())
+}
+
+// valid check the date is not normalized by the time.Date
+func valid(t time.Time, m time.Month, d int) bool {
+	return t.Month() == m && t.Day() == d
+}
+
+// date match the absolute or relative date at i, return the date,
+// the layout of the precision and the period of the day
+func (s *scanner) date(i int) (t time.Time, layout string, end int, period string, ok bool) {
+	if w, end, ok := relWordAt(s, i, relDays); ok {
+		return s.today().AddDate(0, 0, w.n), DateLayout, end, w.period, true
+	}
+
+	if w, k, ok := relWordAt(s, i, relYears); ok {
+		y := s.ref.Year() + w.n
+		if t, layout, end, ok := s.monthDay(k, y); ok {
+			return t, layout, end, "", true
+		}
+		return s.day(y, 1, 1), YearLayout, k, "", true
+	}
+
+	if w, k, ok := relWordAt(s, i, relMonths); ok {
+		t := s.day(s.ref.Year(), s.ref.Month(), 1).AddDate(0, w.n, 0)
+		if n, end, ok := s.dayOfMonth(k); ok {
+			if d := s.day(t.Year(), t.Month(), n); valid(d, t.Month(), n) {
+				return d, DateLayout, end, "", true
+			}
+		}
+		return t, MonthLayout, k, "", true
+	}
+
+	if t, end, ok := s.weekday(i); ok {
+		return t, DateLayout, end, "", true
+	}
+
+	if t, end, ok := s.numericDate(i); ok {
+		return t, DateLayout, end, "", true
+	}
+
+	n, isNum := s.integer(i)
+	if !isNum {
+		return
+	}
+
+	if _, k, ok := s.word(n.end, "年"); ok && isYear(n) {
+		y := int(n.value)
+		if y < 100 {
+			y += 2000
+		}
+
+		if t, layout, end, ok := s.monthDay(k, y); ok {
+			return t, layout, end, "", true
+		}
+		return s.day(y, 1, 1), YearLayout, k, "", true
+	}
+
+	if t, layout, end, ok := s.monthDay(i, s.ref.Year()); ok {
+		return t, layout, end, "", true
+	}
+	return time.Time{}, "", i, "", false
+}
+
+// isYear the number before "年" is a year, such as "2024" and "二四"
+func isYear(n num) bool {
+	if n.value >= 1000 && n.value <= 9999 {
+		return true
+	}
+	return !n.arabic && !n.units && n.end-n.start == 2
+}
+
+// monthDay match the "3月" and "3月5日" at i of the year
+func (s *scanner) monthDay(i, y int) (time.Time, string, int, bool) {
+	n, ok := s.integer(i)
+	if !ok || n.value < 1 || n.value > 12 {
+		return time.Time{}, "", i, false
+	}
+	_, k, ok := s.word(n.end, "月份", "月")
+	if !ok {
+		return time.Time{}, "", i, false
+	}
+
+	m := time.Month(n.value)
+	if d, end, ok := s.dayOfMonth(k); ok {
+		if t := s.day(y, m, d); valid(t, m, d) {
+			return t, DateLayout, end, true
+		}
+	}
+	return s.day(y, m, 1), MonthLayout, k, true
+}
+
+// dayOfMonth match the "5日" and "5号" at i
+func (s *scanner) dayOfMonth(i int) (int, int, bool) {
+	n, ok := s.integer(i)
+	if !ok || n.value < 1 || n.value > 31 {
+		return 0, i, false
+	}
+	if _, end, ok := s.word(n.end, "日", "号"); ok {
+		return int(n.value), end, true
+	}
+	return 0, i, false
+}
+
+// numericDate match the "2024-03-05", "2024/3/5" and "2024.3.5" at i
+func (s *scanner) numericDate(i int) (time.Time, int, bool) {
+	if s.digits(i) != 4 || i+4 >= len(s.rs) {
+		return time.Time{}, i, false
+	}
+	if sep := s.rs[i+4]; sep != '-' && sep != '/' && sep != '.' {
+		return time.Time{}, i, false
+	}
+
+	var parts [3]int
+	j := i
+	for k := 0; k < 3; k++ {
+		if k > 0 {
+			if j >= len(s.rs) || s.rs[j] != s.rs[i+4] {
+				return time.Time{}, i, false
+			}
+			j++
+		}
+
+		c := s.digits(j)
+		if c == 0 || (k > 0 && c > 2) {
+			return time.Time{}, i, false
+		}
+		for ; c > 0; c-- {
+			d, _ := arabic(s.rs[j])
+			parts[k] = parts[k]*10 + d
+			j++
+		}
+	}
+
+	m := time.Month(parts[1])
+	t := s.day(parts[0], m, parts[2])
+	return t, j, valid(t, m, parts[2])
+}
+
+// weekday match the "下周五" and "星期三" at i, the weekday without the
+// prefix is the nearest day on or after the reference day
+func (s *scanner) weekday(i int) (time.Time, int, bool) {
+	w, k, rel := relWordAt(s, i, relWeeks)
+	_, k, ok := s.word(k, "周", "星期", "礼拜")
+	if !ok || k >= len(s.rs) {
+		return time.Time{}, i, false
+	}
+
+	d, ok := weekdays[s.rs[k]]
+	if !ok {
+		return time.Time{}, i, false
+	}
+
+	today := s.today()
+	wd := int(today.Weekday())
+	if wd == 0 {
+		wd = 7
+	}
+
+	t := today.AddDate(0, 0, 7*w.n+d-wd)
+	if !rel && t.Before(today) {
+		t = t.AddDate(0, 0, 7)
+	}
+	return t, k + 1, true
+}
+
+// hour adjust the hour of the period, the "晚上12点" is the 24,
+// the 00:00 of the next day
+func hour(h int, period string) int {
+	switch period {
+	case "", "凌晨", "早上", "早晨", "清晨", "上午", "半夜", "早":
+		if h == 12 && period != "" {
+			return 0
+		}
+	case "中午":
+		if h < 6 {
+			return h + 12
+		}
+	case "晚上", "晚间", "夜里", "夜间", "晚":
+		if h <= 12 {
+			return h + 12
+		}
+	default:
+		if h < 12 {
+			return h + 12
+		}
+	}
+	return h
+}
+
+// clock match the time of the day at i, such as "15:30", "三点半" and
+// "8点20分", the strict clock without the date or the period
+// should be unambiguous, such as "一点" is not a clock
+func (s *scanner) clock(i int, strict bool) (h, m, sec, end int, ok bool) {
+	if c := s.digits(i); c >= 1 && c <= 2 && i+c < len(s.rs) &&
+		(s.rs[i+c] == ':' || s.rs[i+c] == '：') && s.digits(i+c+1) == 2 {
+		h = atoi(s, i, c)
+		m = atoi(s, i+c+1, 2)
+		end = i + c + 3
+		if end < len(s.rs) && s.rs[end] == s.rs[i+c] && s.digits(end+1) == 2 {
+			sec = atoi(s, end+1, 2)
+			end += 3
+		}
+		return h, m, sec, end, h <= 24 && m < 60 && sec < 60
+	}
+
+	n, isNum := s.integer(i)
+	if !isNum || n.value > 24 {
+		return 0, 0, 0, i, false
+	}
+	unit, k, isUnit := s.word(n.end, "点钟", "点", "时")
+	if !isUnit {
+		return 0, 0, 0, i, false
+	}
+	h, end = int(n.value), k
+	exact := n.arabic || unit != "点"
+
+	if _, k, ok := s.word(end, "半"); ok {
+		return h, 30, 0, k, true
+	}
+	if q, k, ok := s.word(end, "一刻", "1刻", "三刻", "3刻"); ok {
+		m = 15
+		if q == "三刻" || q == "3刻" {
+			m = 45
+		}
+		return h, m, 0, k, true
+	}
+
+	if unit == "点钟" {
+		_, end, _ = s.word(end, "整")
+		return h, 0, 0, end, true
+	}
+
+	mn, isMin := s.integer(end)
+	if !isMin || mn.value >= 60 {
+		_, end, _ = s.word(end, "整")
+		return h, 0, 0, end, exact || !strict
+	}
+
+	_, k, hasMin := s.word(mn.end, "分钟", "分")
+	if !hasMin {
+		// the "三点五" is a decimal without the context
+		if strict {
+			return 0, 0, 0, i, false
+		}
+		return h, int(mn.value), 0, mn.end, true
+	}
+	m, end = int(mn.value), k
+
+	if sn, ok := s.integer(end); ok && sn.value < 60 {
+		if _, k, ok := s.word(sn.end, "秒钟", "秒"); ok {
+			sec, end = int(sn.value), k
+		}
+	}
+	return h, m, sec, end, true
+}
+
+func atoi(s *scanner, i, c int) (v int) {
+	for ; c > 0; c-- {
+		d, _ := arabic(s.rs[i])
+		v = v*10 + d
+		i++
+	}
+	return
+}
+
+// validClock the clock is in the day, or the 24:00 of the day
+func validClock(h, m, sec int) bool {
+	return h < 24 || h == 24 && m == 0 && sec == 0
+}
+
+func timeExpr(t time.Time) Expr {
+	return Expr{Type: Time, Time: t, Norm: t.Format(TimeLayout)}
+}
+
+// dateTime match the date, the time of the day or both at i,
+// such as "2024年3月5日", "明天下午三点半" and "15:30"
+func (s *scanner) dateTime(i int) (Expr, int, bool) {
+	t, layout, end, period, ok := s.date(i)
+	if ok {
+		if layout == DateLayout {
+			k := end
+			if k < len(s.rs) && s.rs[k] == ' ' {
+				k++
+			}
+			if p, k1, ok := s.word(k, periods...); ok {
+				period, k = p, k1
+			}
+
+			if h, m, sec, k2, ok := s.clock(k, false); ok {
+				h = hour(h, period)
+				if validClock(h, m, sec) {
+					return timeExpr(t.Add(time.Duration(h)*time.Hour +
+						time.Duration(m)*time.Minute + time.Duration(sec)*time.Second)), k2, true
+				}
+			}
+		}
+
+		return Expr{Type: Date, Time: t, Norm: t.Format(layout)}, end, true
+	}
+
+	k, strict := i, true
+	if p, k1, ok := s.word(i, periods...); ok {
+		period, k, strict = p, k1, false
+	}
+
+	h, m, sec, end, ok := s.clock(k, strict)
+	if !ok {
+		return Expr{}, i, false
+	}
+
+	h = hour(h, period)
+	if !validClock(h, m, sec) {
+		return Expr{}, i, false
+	}
+	t = s.today().Add(time.Duration(h)*time.Hour +
+		time.Duration(m)*time.Minute + time.Duration(sec)*time.Second)
+	return timeExpr(t), end, true
+}
+
+// durUnit the duration unit
+type durUnit struct {
+	word string
+	half float64
+	sec  float64
+	// iso the ISO 8601 designator, time is in the time part
+	iso  string
+	time bool
+}
+
+// rank the order of the unit in the ISO 8601 duration
+func (u durUnit) rank() int {
+	if u.time {
+		return 4 + strings.Index("HMS", u.iso)
+	}
+	return strings.Index("YMWD", u.iso)
+}
+
+var durations = []durUnit{
+	{"个半小时", 0.5, 3600, "H", true}, {"个半钟头", 0.5, 3600, "H", true},
+	{"个小时", 0, 3600, "H", true}, {"小时", 0, 3600, "H", true},
+	{"个钟头", 0, 3600, "H", true}, {"钟头", 0, 3600, "H", true},
+	{"分钟", 0, 60, "M", true}, {"秒钟", 0, 1, "S", true}, {"秒", 0, 1, "S", true},
+	{"天", 0, 86400, "D", false}, {"个星期", 0, 604800, "W", false},
+	{"星期", 0, 604800, "W", false}, {"个礼拜", 0, 604800, "W", false},
+	{"礼拜", 0, 604800, "W", false}, {"周", 0, 604800, "W", false},
+	{"个半月", 0.5, 2592000, "M", false}, {"个月", 0, 2592000, "M", false},
+	{"年", 0, 31536000, "Y", false},
+}
+
+// durPart the number of the duration unit
+type durPart struct {
+	v float64
+	u durUnit
+}
+
+// durationUnit match the number and the duration unit at i,
+// such as "两个半小时" and "三天"
+func (s *scanner) durationUnit(i int) (durPart, int, bool) {
+	var (
+		v    float64
+		k    int
+		half bool
+	)
+	if _, end, ok := s.word(i, "半个", "半"); ok {
+		v, k, half = 0.5, end, true
+	} else {
+		n, ok := s.number(i)
+		if !ok {
+			return durPart{}, i, false
+		}
+		v, k = n.value, n.end
+	}
+
+	for _, u := range durations {
+		_, end, ok := s.word(k, u.word)
+		if !ok && half && u.iso == "M" && !u.time {
+			// the "半个月"
+			_, end, ok = s.word(k, "月")
+		}
+		if !ok || (half && u.half > 0) {
+			continue
+		}
+
+		return durPart{v: v + u.half, u: u}, end, true
+	}
+
+	return durPart{}, i, false
+}
+
+// duration match the duration at i, such as "两个半小时", "三天" and
+// "1小时30分钟", the units are chained from the larger to the smaller,
+// the duration with "后" or "前" is resolved to the date or time,
+// such as "三天后"
+func (s *scanner) duration(i int) (Expr, int, bool) {
+	p, end, ok := s.durationUnit(i)
+	if !ok {
+		return Expr{}, i, false
+	}
+
+	parts := []durPart{p}
+	for {
+		k := end
+		if _, k1, ok := s.word(k, "零"); ok {
+			k = k1
+		}
+		p, k, ok := s.durationUnit(k)
+		if !ok || p.u.rank() <= parts[len(parts)-1].u.rank() {
+			break
+		}
+		parts, end = append(parts, p), k
+	}
+
+	var (
+		e          = Expr{Type: Duration}
+		date, tm   strings.Builder
+		isTime     bool
+		fractional bool
+	)
+	for _, p := range parts {
+		e.Value += p.v * p.u.sec
+		if p.u.time {
+			tm.WriteString(format(p.v) + p.u.iso)
+			isTime = true
+		} else {
+			date.WriteString(format(p.v) + p.u.iso)
+		}
+		fractional = fractional || float64(int(p.v)) != p.v
+	}
+	e.Norm = "P" + date.String()
+	if tm.Len() > 0 {
+		e.Norm += "T" + tm.String()
+	}
+
+	dir, k, ok := s.word(end, "以后", "之后", "后", "以前", "之前", "前")
+	if !ok {
+		return e, end, true
+	}
+
+	sign := 1
+	if dir == "以前" || dir == "之前" || dir == "前" {
+		sign = -1
+	}
+	if len(parts) == 1 {
+		return s.relative(parts[0].v, parts[0].u.iso, parts[0].u.time, sign), k, true
+	}
+	if isTime || fractional {
+		t := s.ref.Add(time.Duration(float64(sign) * e.Value * float64(time.Second)))
+		return timeExpr(t.Truncate(time.Second)), k, true
+	}
+
+	// the calendar units, such as "1年3个月后"
+	t := s.today()
+	for _, p := range parts {
+		n := sign * int(p.v)
+		switch p.u.iso {
+		case "Y":
+			t = t.AddDate(n, 0, 0)
+		case "M":
+			t = t.AddDate(0, n, 0)
+		case "W":
+			t = t.AddDate(0, 0, 7*n)
+		default:
+			t = t.AddDate(0, 0, n)
+		}
+	}
+	return Expr{Type: Date, Time: t, Norm: t.Format(DateLayout)}, k, true
+}
+
+// relative resolve the duration before or after the reference time
+func (s *scanner) relative(v float64, iso string, isTime bool, sign int) Expr {
+	n := int(v)
+	if isTime || float64(n) != v {
+		var d time.Duration
+		switch {
+		case iso == "H":
+			d = time.Hour
+		case iso == "M" && isTime:
+			d = time.Minute
+		case iso == "S":
+			d = time.Second
+		case iso == "D":
+			d = 24 * time.Hour
+		case iso == "W":
+			d = 7 * 24 * time.Hour
+		case iso == "M":
+			d = 30 * 24 * time.Hour
+		default:
+			d = 365 * 24 * time.Hour
+		}
+
+		t := s.ref.Add(time.Duration(float64(sign) * v * float64(d))).Truncate(time.Second)
+		return timeExpr(t)
+	}
+
+	n *= sign
+	t := s.today()
+	switch iso {
+	case "D":
+		t = t.AddDate(0, 0, n)
+	case "W":
+		t = t.AddDate(0, 0, 7*n)
+	case "M":
+		t = t.AddDate(0, n, 0)
+	default:
+		t = t.AddDate(n, 0, 0)
+	}
+	return Expr{Type: Date, Time: t, Norm: t.Format(DateLayout)}
+}
@@ -0,0 +1,605 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+/*
+Package normalize is the recognition and normalization of the Chinese
+numeral, money, percentage, quantity, date, time and duration expressions,
+the relative times are resolved against the reference time:
+
+	p := normalize.Parser{Ref: time.Now()}
+	exprs := p.Parse("明天下午三点半付三千五百元")
+	// 明天下午三点半 time 2024-03-06T15:30:00
+	// 三千五百元     money 3500 CNY
+*/
+package normalize
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-ego/gse"
+)
+
+// The expression types
+const (
+	Number   = "number"
+	Money    = "money"
+	Percent  = "percent"
+	Quantity = "quantity"
+	Date     = "date"
+	Time     = "time"
+	Duration = "duration"
+)
+
+// Expr the recognized expression
+type Expr struct {
+	Text string
+	// Type the expression type, such as Money and Date
+	Type string
+	// Start and End the bytes offsets of the expression in the text
+	Start, End int
+
+	// Value the number, the amount of the money, the percentage,
+	// or the seconds of the duration
+	Value float64
+	// Unit the currency code, such as "CNY", or the quantity unit
+	Unit string
+	// Time the resolved time of the date and time
+	Time time.Time
+	// Norm the normalized text, such as "3500 CNY", "2024-03-05",
+	// "2024-03-06T15:30:00" and "PT1H30M"
+	Norm string
+}
+
+// Parser the expression parser
+type Parser struct {
+	// Ref the reference time of the relative expressions,
+	// use the time.Now if it is zero
+	Ref time.Time
+}
+
+// Parse parse the expressions of the text with the reference time
+func Parse(text string, ref ...time.Time) []Expr {
+	var p Parser
+	if len(ref) > 0 {
+		p.Ref = ref[0]
+	}
+	return p.Parse(text)
+}
+
+// scanner the runes of the text with the bytes offsets
+type scanner struct {
+	text string
+	rs   []rune
+	off  []int
+	ref  time.Time
+}
+
+func newScanner(text string, ref time.Time) *scanner {
+	s := &scanner{text: text, ref: ref}
+	for i, r := range text {
+		s.rs = append(s.rs, r)
+		s.off = append(s.off, i)
+	}
+	s.off = append(s.off, len(text))
+	return s
+}
+
+// word return the end if the text at i starts with one of the words,
+// the words should be the longest first
+func (s *scanner) word(i int, words ...string) (string, int, bool) {
+	if i >= len(s.rs) {
+		return "", i, false
+	}
+
+	for _, w := range words {
+		if strings.HasPrefix(s.text[s.off[i]:], w) {
+			return w, i + utf8.RuneCountInString(w), true
+		}
+	}
+	return "", i, false
+}
+
+// Parse parse the expressions of the text
+func (p *Parser) Parse(text string) (exprs []Expr) {
+	ref := p.Ref
+	if ref.IsZero() {
+		ref = time.Now()
+	}
+
+	s := newScanner(text, ref)
+	for i := 0; i < len(s.rs); {
+		e, end, ok := s.match(i)
+		if !ok {
+			i++
+			continue
+		}
+
+		e.Start, e.End = s.off[i], s.off[end]
+		e.Text = text[e.Start:e.End]
+		exprs = append(exprs, e)
+		i = end
+	}
+
+	return
+}
+
+// match match the expression at i
+func (s *scanner) match(i int) (Expr, int, bool) {
+	if e, end, ok := s.dateTime(i); ok {
+		return e, end, true
+	}
+	if e, end, ok := s.percent(i); ok {
+		return e, end, true
+	}
+	if e, end, ok := s.money(i); ok {
+		return e, end, true
+	}
+	if e, end, ok := s.duration(i); ok {
+		return e, end, true
+	}
+
+	n, ok := s.number(i)
+	if !ok {
+		return Expr{}, i, false
+	}
+	if e, end, ok := s.quantity(n); ok {
+		return e, end, true
+	}
+
+	// the single Chinese numeral is usually a part of the word, such as "一起"
+	if !n.arabic && n.end-n.start == 1 {
+		return Expr{}, i, false
+	}
+	return Expr{Type: Number, Value: n.value, Norm: format(n.value)}, n.end, true
+}
+
+func format(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+var (
+	cnDigits = map[rune]float64{
+		'零': 0, '〇': 0, '○': 0, '一': 1, '壹': 1, '幺': 1, '二': 2, '贰': 2,
+		'两': 2, '三': 3, '叁': 3, '四': 4, '肆': 4, '五': 5, '伍': 5, '六': 6,
+		'陆': 6, '七': 7, '柒': 7, '八': 8, '捌': 8, '九': 9, '玖': 9,
+	}
+
+	cnUnits = map[rune]float64{
+		'十': 10, '拾': 10, '百': 100, '佰': 100, '千': 1000, '仟': 1000,
+		'万': 1e4, '萬': 1e4, '亿': 1e8, '億': 1e8,
+	}
+)
+
+func arabic(r rune) (int, bool) {
+	switch {
+	case r >= '0' && r <= '9':
+		return int(r - '0'), true
+	case r >= '０' && r <= '９':
+		return int(r - '０'), true
+	}
+	return 0, false
+}
+
+// num the scanned number
+type num struct {
+	value      float64
+	start, end int
+	// arabic has the Arabic digits, units has the Chinese units
+	arabic, units bool
+	// integer the number has no decimal part
+	integer bool
+}
+
+type numTok struct {
+	v    float64
+	unit bool
+}
+
+// arabicNum scan the Arabic number with the thousands separators
+// and the decimal point at i
+func (s *scanner) arabicNum(i int) (float64, int, bool) {
+	var b strings.Builder
+	j := i
+	integer := true
+	for j < len(s.rs) {
+		if d, ok := arabic(s.rs[j]); ok {
+			b.WriteByte(byte('0' + d))
+			j++
+			continue
+		}
+
+		r := s.rs[j]
+		// the thousands separator is followed by three digits
+		if r == ',' && b.Len() > 0 && integer && s.digits(j+1) == 3 {
+			j++
+			continue
+		}
+
+		if (r == '.' || r == '．') && integer && j+1 < len(s.rs) {
+			if _, ok := arabic(s.rs[j+1]); ok {
+				b.WriteByte('.')
+				integer = false
+				j++
+				continue
+			}
+		}
+		break
+	}
+
+	v, _ := strconv.ParseFloat(b.String(), 64)
+	return v, j, integer
+}
+
+// digits return the count of the Arabic digits at i
+func (s *scanner) digits(i int) int {
+	j := i
+	for j < len(s.rs) {
+		if _, ok := arabic(s.rs[j]); !ok {
+			break
+		}
+		j++
+	}
+	return j - i
+}
+
+// number scan the Arabic or Chinese number at i, such as "3,500",
+// "3.5万", "三千五百", "三点五" and "二〇二四"
+func (s *scanner) number(i int) (num, bool) {
+	return s.scan(i, true)
+}
+
+// integer scan the integer at i, without the Chinese decimal point
+func (s *scanner) integer(i int) (num, bool) {
+	n, ok := s.scan(i, false)
+	return n, ok && n.integer
+}
+
+func (s *scanner) scan(i int, decimal bool) (n num, ok bool) {
+	n.start, n.integer = i, true
+	var toks []numTok
+	digits := 0
+	lastArabic := false
+
+	j := i
+	for j < len(s.rs) {
+		r := s.rs[j]
+		if _, ok := arabic(r); ok {
+			if len(toks) > 0 && !toks[len(toks)-1].unit {
+				break
+			}
+
+			v, k, integer := s.arabicNum(j)
+			toks = append(toks, numTok{v: v})
+			n.arabic, lastArabic = true, true
+			n.integer = n.integer && integer
+			digits++
+			j = k
+			continue
+		}
+
+		if d, ok := cnDigits[r]; ok {
+			if lastArabic && !toks[len(toks)-1].unit {
+				break
+			}
+
+			toks = append(toks, numTok{v: d})
+			lastArabic = false
+			digits++
+			j++
+			continue
+		}
+
+		if u, ok := cnUnits[r]; ok {
+			// the number can start with "十", such as "十五"
+			if len(toks) == 0 && u != 10 {
+				break
+			}
+			toks = append(toks, numTok{v: u, unit: true})
+			n.units = true
+			j++
+			continue
+		}
+
+		// the Chinese decimal point, such as "三点五"
+		if r == '点' && decimal && len(toks) > 0 && n.integer && j+1 < len(s.rs) {
+			frac, k := 0.0, j+1
+			scale := 0.1
+			for ; k < len(s.rs); k++ {
+				d, ok := cnDigits[s.rs[k]]
+				if !ok {
+					a, ok1 := arabic(s.rs[k])
+					if !ok1 {
+						break
+					}
+					d = float64(a)
+				}
+				frac += d * scale
+				scale /= 10
+			}
+			if k == j+1 {
+				break
+			}
+
+			v := eval(toks) + frac
+			for ; k < len(s.rs); k++ {
+				u, ok := cnUnits[s.rs[k]]
+				if !ok || u < 100 {
+					break
+				}
+				v *= u
+			}
+			toks = []numTok{{v: v}}
+			n.integer = false
+			j = k
+			break
+		}
+
+		break
+	}
+
+	if digits == 0 && (len(toks) == 0 || toks[0].v != 10) {
+		return n, false
+	}
+
+	n.value, n.end = eval(toks), j
+	return n, true
+}
+
+// eval evaluate the number tokens
+func eval(toks []numTok) float64 {
+	units := false
+	for _, t := range toks {
+		units = units || t.unit
+	}
+
+	if !units {
+		// the positional digits, such as "二〇二四"
+		v := 0.0
+		for _, t := range toks {
+			v = v*10 + t.v
+		}
+		if len(toks) == 1 {
+			v = toks[0].v
+		}
+		return v
+	}
+
+	var (
+		total, section, number float64
+		lastUnit               float64
+		zero, afterUnit        bool
+	)
+	for k, t := range toks {
+		if !t.unit {
+			number = t.v
+			zero = zero || t.v == 0
+			afterUnit = k > 0 && toks[k-1].unit
+			continue
+		}
+
+		u := t.v
+		switch {
+		case u >= 1e8:
+			total = (total + section + number) * u
+			section = 0
+		case u >= 1e4:
+			section = (section + number) * u
+		default:
+			if number == 0 && u == 10 {
+				number = 1
+			}
+			section += number * u
+		}
+		number, lastUnit, zero = 0, u, false
+	}
+
+	// the abbreviation, such as "三千五" is 3500
+	if number > 0 && !zero && afterUnit && lastUnit >= 100 && !toks[len(toks)-1].unit {
+		number *= lastUnit / 10
+	}
+
+	return total + section + number
+}
+
+var currencies = []struct {
+	word, code string
+}{
+	{"人民币", "CNY"}, {"美元", "USD"}, {"美金", "USD"}, {"欧元", "EUR"},
+	{"英镑", "GBP"}, {"日元", "JPY"}, {"港元", "HKD"}, {"港币", "HKD"},
+	{"块钱", "CNY"}, {"元", "CNY"}, {"块", "CNY"}, {"圆", "CNY"},
+}
+
+var symbols = map[rune]string{
+	'¥': "CNY", '￥': "CNY", '$': "USD", '€': "EUR", '£': "GBP",
+}
+
+func moneyExpr(v float64, code string) Expr {
+	return Expr{Type: Money, Value: v, Unit: code, Norm: format(v) + " " + code}
+}
+
+// money match the money at i, such as "三千五百元", "3.5万美元" and "¥200"
+func (s *scanner) money(i int) (Expr, int, bool) {
+	if code, ok := symbols[s.rs[i]]; ok {
+		if n, ok := s.number(i + 1); ok && n.arabic {
+			end := n.end
+			if _, k, ok := s.word(end, "元"); ok && code == "CNY" {
+				end = k
+			}
+			return moneyExpr(n.value, code), end, true
+		}
+		return Expr{}, i, false
+	}
+
+	n, ok := s.number(i)
+	if !ok {
+		return Expr{}, i, false
+	}
+
+	for _, c := range currencies {
+		_, end, ok := s.word(n.end, c.word)
+		if !ok {
+			continue
+		}
+
+		v := n.value
+		if c.code == "CNY" && n.integer {
+			// the jiao and fen, such as "三块五" and "十元五角二分"
+			v, end = s.jiao(v, end, c.word == "块" || c.word == "块钱")
+		}
+		return moneyExpr(v, c.code), end, true
+	}
+
+	return Expr{}, i, false
+}
+
+// jiao add the jiao and fen after the yuan at i
+func (s *scanner) jiao(v float64, i int, short bool) (float64, int) {
+	d, ok := s.digit(i)
+	if !ok {
+		return v, i
+	}
+
+	if _, end, ok := s.word(i+1, "角", "毛"); ok {
+		v += float64(d) / 10
+		if f, ok := s.digit(end); ok {
+			if _, k, ok := s.word(end+1, "分"); ok {
+				return v + float64(f)/100, k
+			}
+		}
+		return v, end
+	}
+
+	if _, end, ok := s.word(i+1, "分"); ok {
+		return v + float64(d)/100, end
+	}
+
+	// the "三块五"
+	if short {
+		if _, _, ok := s.word(i+1, "十", "百", "千", "万"); !ok {
+			return v + float64(d)/10, i + 1
+		}
+	}
+	return v, i
+}
+
+// digit return the single digit at i
+func (s *scanner) digit(i int) (int, bool) {
+	if i >= len(s.rs) {
+		return 0, false
+	}
+	if d, ok := arabic(s.rs[i]); ok {
+		return d, true
+	}
+	if d, ok := cnDigits[s.rs[i]]; ok {
+		return int(d), true
+	}
+	return 0, false
+}
+
+// percent match the percentage at i, such as "百分之三十五" and "35%"
+func (s *scanner) percent(i int) (Expr, int, bool) {
+	if w, end, ok := s.word(i, "百分之", "千分之"); ok {
+		n, ok := s.number(end)
+		if !ok {
+			return Expr{}, i, false
+		}
+
+		v := n.value
+		if w == "千分之" {
+			v /= 10
+		}
+		return Expr{Type: Percent, Value: v, Norm: format(v) + "%"}, n.end, true
+	}
+
+	n, ok := s.number(i)
+	if !ok {
+		return Expr{}, i, false
+	}
+	if _, end, ok := s.word(n.end, "%", "％"); ok {
+		return Expr{Type: Percent, Value: n.value, Norm: format(n.value) + "%"}, end, true
+	}
+
+	return Expr{}, i, false
+}
+
+var units = []struct {
+	word, unit string
+}{
+	{"平方公里", "km2"}, {"平方米", "m2"}, {"平米", "m2"}, {"公里", "km"},
+	{"千米", "km"}, {"厘米", "cm"}, {"毫米", "mm"}, {"英里", "mi"}, {"米", "m"},
+	{"公斤", "kg"}, {"千克", "kg"}, {"毫克", "mg"}, {"克", "g"}, {"吨", "t"},
+	{"毫升", "mL"}, {"升", "L"}, {"公顷", "ha"}, {"摄氏度", "°C"}, {"℃", "°C"},
+}
+
+// quantity match the number with the measure unit, such as "5公里"
+func (s *scanner) quantity(n num) (Expr, int, bool) {
+	for _, u := range units {
+		if _, end, ok := s.word(n.end, u.word); ok {
+			return Expr{Type: Quantity, Value: n.value, Unit: u.unit,
+				Norm: format(n.value) + " " + u.unit}, end, true
+		}
+	}
+
+	return Expr{}, n.start, false
+}
+
+// Merge merge the words of the gse output in the expressions to
+// one word, the POS is "t" of the date, time and duration, "m" of others
+func Merge(words []gse.SegPos, ref ...time.Time) []gse.SegPos {
+	var b strings.Builder
+	starts := make([]int, len(words))
+	for i, w := range words {
+		starts[i] = b.Len()
+		b.WriteString(w.Text)
+	}
+
+	text := b.String()
+	exprs := Parse(text, ref...)
+	result := make([]gse.SegPos, 0, len(words))
+
+	k := 0
+	for i := 0; i < len(words); {
+		for k < len(exprs) && exprs[k].End <= starts[i] {
+			k++
+		}
+
+		end := starts[i] + len(words[i].Text)
+		if k >= len(exprs) || exprs[k].Start >= end {
+			result = append(result, words[i])
+			i++
+			continue
+		}
+
+		// merge the words overlapping the expression
+		j := i
+		for j+1 < len(words) && starts[j+1] < exprs[k].End {
+			j++
+		}
+
+		pos := "m"
+		switch exprs[k].Type {
+		case Date, Time, Duration:
+			pos = "t"
+		}
+
+		last := starts[j] + len(words[j].Text)
+		result = append(result, gse.SegPos{Text: text[starts[i]:last], Pos: pos})
+		i = j + 1
+	}
+
+	return result
+}
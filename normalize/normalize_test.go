@@ -0,0 +1,104 @@
+package normalize
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-ego/gse"
+	"github.com/vcaesar/tt"
+)
+
+var ref = time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+
+func norm(text string) string {
+	var s []string
+	for _, e := range Parse(text, ref) {
+		s = append(s, e.Type+":"+e.Norm)
+	}
+	return strings.Join(s, ", ")
+}
+
+func TestNumber(t *testing.T) {
+	tt.Equal(t, "number:3500", norm("三千五百"))
+	tt.Equal(t, "number:3500", norm("三千五"))
+	tt.Equal(t, "number:23000", norm("两万三"))
+	tt.Equal(t, "number:105", norm("一百零五"))
+	tt.Equal(t, "number:100005", norm("十万零五"))
+	tt.Equal(t, "number:120000000", norm("一亿二千万"))
+	tt.Equal(t, "number:3.5", norm("三点五"))
+	tt.Equal(t, "number:1400000000", norm("人口14亿"))
+	tt.Equal(t, "number:3500.5", norm("3,500.5"))
+	tt.Equal(t, "", norm("我们一起统一"))
+
+	tt.Equal(t, "money:3500 CNY", norm("三千五百元"))
+	tt.Equal(t, "money:35000 USD", norm("3.5万美元"))
+	tt.Equal(t, "money:3.5 CNY", norm("三块五"))
+	tt.Equal(t, "money:10.52 CNY", norm("十元五角二分"))
+	tt.Equal(t, "money:200 CNY", norm("¥200"))
+
+	tt.Equal(t, "percent:35%", norm("百分之三十五"))
+	tt.Equal(t, "percent:35%", norm("35%"))
+	tt.Equal(t, "quantity:5 km", norm("5公里"))
+	tt.Equal(t, "quantity:2.5 kg", norm("两点五公斤"))
+}
+
+func TestDateTime(t *testing.T) {
+	tt.Equal(t, "date:2024-03-05", norm("二〇二四年三月五日"))
+	tt.Equal(t, "date:2024-12-25", norm("12月25日"))
+	tt.Equal(t, "date:2024-12", norm("今年12月"))
+	tt.Equal(t, "date:2024-04-05", norm("下个月5号"))
+	tt.Equal(t, "date:2024-03-08", norm("2024/3/8"))
+	tt.Equal(t, "date:2024-03-10", norm("周日"))
+	tt.Equal(t, "number:2024, number:2, number:30", norm("2024/2/30"))
+
+	tt.Equal(t, "time:2024-03-06T15:30:00", norm("明天下午三点半"))
+	tt.Equal(t, "time:2024-03-15T10:00:00", norm("下周五上午10点"))
+	tt.Equal(t, "time:2024-03-05T20:15:00", norm("晚上八点一刻"))
+	tt.Equal(t, "time:2024-03-05T15:30:00", norm("2024-03-05 15:30"))
+	tt.Equal(t, "time:2024-03-05T01:30:20", norm("凌晨1:30:20"))
+	tt.Equal(t, "time:2024-03-04T21:00:00", norm("昨晚九点"))
+	tt.Equal(t, "", norm("有一点累"))
+
+	tt.Equal(t, "duration:PT1.5H", norm("一个半小时"))
+	tt.Equal(t, "duration:P3Y", norm("三年"))
+	tt.Equal(t, "duration:P0.5M", norm("半个月"))
+	tt.Equal(t, "date:2024-03-08", norm("三天后"))
+	tt.Equal(t, "date:2022-03-05", norm("两年前"))
+	tt.Equal(t, "time:2024-03-05T10:30:00", norm("半小时后"))
+	tt.Equal(t, "duration:PT1H30M", norm("1小时30分钟"))
+	tt.Equal(t, "duration:P1DT2H", norm("一天两个小时"))
+	tt.Equal(t, "duration:P1Y3M", norm("一年零三个月"))
+	tt.Equal(t, "time:2024-03-05T11:30:00", norm("1小时30分钟后"))
+	tt.Equal(t, "date:2025-06-05", norm("1年3个月后"))
+	tt.Equal(t, "duration:PT30M, duration:PT1H", norm("30分钟1小时"))
+
+	tt.Equal(t, "time:2024-03-06T00:00:00", norm("晚上12点"))
+	tt.Equal(t, "time:2024-03-07T00:00:00", norm("明天晚上12点"))
+	tt.Equal(t, "time:2024-03-05T12:00:00", norm("中午12点"))
+
+	e := Parse("预订明天下午三点半，两个人", ref)
+	tt.Equal(t, 1, len(e))
+	tt.Equal(t, 6, e[0].Start)
+	tt.Equal(t, "明天下午三点半", e[0].Text)
+	tt.Equal(t, 15, e[0].Time.Hour())
+}
+
+func TestMerge(t *testing.T) {
+	words := []gse.SegPos{
+		{Text: "明天", Pos: "t"}, {Text: "下午", Pos: "t"}, {Text: "三点", Pos: "m"},
+		{Text: "半", Pos: "m"}, {Text: "付", Pos: "v"}, {Text: "三千", Pos: "m"},
+		{Text: "五百", Pos: "m"}, {Text: "元", Pos: "m"},
+	}
+
+	m := Merge(words, ref)
+	tt.Equal(t, 3, len(m))
+	tt.Equal(t, "明天下午三点半", m[0].Text)
+	tt.Equal(t, "t", m[0].Pos)
+	tt.Equal(t, "付", m[1].Text)
+	tt.Equal(t, "三千五百元", m[2].Text)
+	tt.Equal(t, "m", m[2].Pos)
+
+	var p Parser
+	tt.Equal(t, time.Now().Year(), p.Parse("今天")[0].Time.Year())
+}
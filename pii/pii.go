@@ -0,0 +1,473 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+/*
+Package pii is the detection and masking of the personal identifiable
+information, such as the Chinese ID card numbers, the mobile numbers,
+the bank cards, the emails, the IPs and the person names of the ner:
+
+	m := pii.New(pii.Options{Names: &recognizer, Strategy: pii.Partial})
+	text, spans := m.Mask("张三的手机号是13812345678")
+	// 张*的手机号是138****5678
+*/
+package pii
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-ego/gse/ner"
+)
+
+// The PII kinds
+const (
+	IDCard   = "id_card"
+	Phone    = "phone"
+	BankCard = "bank_card"
+	Email    = "email"
+	IP       = "ip"
+	Name     = "name"
+)
+
+// Strategy the masking strategy
+type Strategy int
+
+const (
+	// Redact replace the PII with the kind, such as "[PHONE]"
+	Redact Strategy = iota
+	// Partial mask the middle of the PII, such as "138****5678"
+	Partial
+	// Hash replace the PII with the keyed HMAC-SHA256 hash,
+	// such as "[PHONE:1a2b3c4d5e6f]", the same PII has the same hash,
+	// it is the Redact without the Options.Key
+	Hash
+)
+
+// Span the detected PII
+type Span struct {
+	Kind string
+	Text string
+	// Start and End the bytes offsets of the PII in the text
+	Start, End int
+	// Masked the replacement of the PII
+	Masked string
+}
+
+// Detector detect the PII spans of the text
+type Detector func(text string) []Span
+
+// Options the masker options
+type Options struct {
+	// Strategy the default masking strategy
+	Strategy Strategy
+	// Strategies the masking strategies of the kinds
+	Strategies map[string]Strategy
+	// Kinds the detected kinds, default is all
+	Kinds []string
+	// Mask the mask character of the Partial, default is '*'
+	Mask rune
+	// Key the secret HMAC key of the Hash, it is required by the Hash,
+	// the unkeyed hash of the phone or ID numbers can be enumerated
+	Key []byte
+	// Names the recognizer of the person names, nil disables the names
+	Names *ner.Recognizer
+}
+
+// Masker the PII detector and masker
+type Masker struct {
+	opt       Options
+	detectors []Detector
+}
+
+// New create a new Masker with the builtin detectors
+func New(opts ...Options) *Masker {
+	var opt Options
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if opt.Mask == 0 {
+		opt.Mask = '*'
+	}
+
+	m := &Masker{opt: opt}
+	builtin := map[string]Detector{
+		IDCard: DetectIDCard, Phone: DetectPhone, BankCard: DetectBankCard,
+		Email: DetectEmail, IP: DetectIP,
+	}
+	if opt.Names != nil {
+		builtin[Name] = m.detectNames
+	}
+
+	kinds := opt.Kinds
+	if len(kinds) == 0 {
+		kinds = []string{IDCard, Phone, BankCard, Email, IP, Name}
+	}
+	for _, k := range kinds {
+		if d, ok := builtin[k]; ok {
+			m.detectors = append(m.detectors, d)
+		}
+	}
+
+	return m
+}
+
+// Add add the custom detector, the earlier detector wins the overlapping
+func (m *Masker) Add(d Detector) {
+	m.detectors = append(m.detectors, d)
+}
+
+// Detect detect the PII spans of the text without the overlapping,
+// sorted by the offsets
+func (m *Masker) Detect(text string) []Span {
+	var (
+		spans []Span
+		used  []Span
+	)
+	for _, d := range m.detectors {
+		for _, s := range d(text) {
+			overlap := false
+			for _, u := range used {
+				if s.Start < u.End && u.Start < s.End {
+					overlap = true
+					break
+				}
+			}
+			if !overlap {
+				used = append(used, s)
+				spans = append(spans, s)
+			}
+		}
+	}
+
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].Start < spans[j].Start
+	})
+	return spans
+}
+
+// Mask mask the PII of the text, return the rewritten text and the spans
+func (m *Masker) Mask(text string) (string, []Span) {
+	spans := m.Detect(text)
+
+	var b strings.Builder
+	last := 0
+	for i := range spans {
+		spans[i].Masked = m.mask(spans[i])
+		b.WriteString(text[last:spans[i].Start])
+		b.WriteString(spans[i].Masked)
+		last = spans[i].End
+	}
+	b.WriteString(text[last:])
+
+	return b.String(), spans
+}
+
+// mask return the replacement of the span by the strategy
+func (m *Masker) mask(s Span) string {
+	strategy := m.opt.Strategy
+	if st, ok := m.opt.Strategies[s.Kind]; ok {
+		strategy = st
+	}
+
+	kind := strings.ToUpper(s.Kind)
+	switch strategy {
+	case Partial:
+		return m.partial(s)
+	case Hash:
+		if len(m.opt.Key) == 0 {
+			break
+		}
+		mac := hmac.New(sha256.New, m.opt.Key)
+		mac.Write([]byte(s.Text))
+		return "[" + kind + ":" + hex.EncodeToString(mac.Sum(nil)[:6]) + "]"
+	}
+	return "[" + kind + "]"
+}
+
+// keep mask the runes except the head and tail runes
+func (m *Masker) keep(text string, head, tail int) string {
+	n := utf8.RuneCountInString(text)
+	if head+tail >= n {
+		head, tail = 0, 0
+	}
+
+	var b strings.Builder
+	i := 0
+	for _, r := range text {
+		if i < head || i >= n-tail {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(m.opt.Mask)
+		}
+		i++
+	}
+	return b.String()
+}
+
+// keepDigits mask the digits except the head and tail digits,
+// the separators are kept
+func (m *Masker) keepDigits(text string, head, tail int) string {
+	n := digits(text)
+	b := []rune(text)
+	k := 0
+	for i, r := range b {
+		if r < '0' || r > '9' {
+			continue
+		}
+		if k >= head && k < n-tail {
+			b[i] = m.opt.Mask
+		}
+		k++
+	}
+	return string(b)
+}
+
+func (m *Masker) partial(s Span) string {
+	switch s.Kind {
+	case IDCard:
+		return m.keep(s.Text, 6, 4)
+	case Phone:
+		// keep the prefix and the first 3 digits of the mobile number
+		return m.keepDigits(s.Text, digits(s.Text)-8, 4)
+	case BankCard:
+		return m.keepDigits(s.Text, 4, 4)
+	case Email:
+		i := strings.LastIndex(s.Text, "@")
+		return m.keep(s.Text[:i], 1, 0) + s.Text[i:]
+	case IP:
+		if strings.Contains(s.Text, ".") {
+			// keep the first two IPv4 octets
+			parts := strings.Split(s.Text, ".")
+			return parts[0] + "." + parts[1] + "." +
+				string(m.opt.Mask) + "." + string(m.opt.Mask)
+		}
+		return m.keep(s.Text, 4, 0)
+	case Name:
+		return m.keep(s.Text, 1, 0)
+	}
+	return m.keep(s.Text, 1, 1)
+}
+
+var (
+	// the numeric regexps are anchored and matched at every start
+	reIDCard = regexp.MustCompile(`^\d{17}[\dXx]`)
+	rePhone  = regexp.MustCompile(`^(?:\+?86[- ]?)?1[3-9]\d(?:[- ]?\d{4}){2}`)
+	reIPv4   = regexp.MustCompile(`^\d{1,3}(?:\.\d{1,3}){3}`)
+	reEmail  = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	reIPv6   = regexp.MustCompile(`[0-9A-Fa-f]{0,4}(?::[0-9A-Fa-f]{0,4}){2,7}`)
+)
+
+func digits(text string) (n int) {
+	for i := 0; i < len(text); i++ {
+		if text[i] >= '0' && text[i] <= '9' {
+			n++
+		}
+	}
+	return
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+func isAlnum(b byte) bool {
+	return b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// bounded the match is not a part of the longer alphanumeric string
+func bounded(text string, start, end int) bool {
+	return (start == 0 || !isAlnum(text[start-1])) &&
+		(end == len(text) || !isAlnum(text[end]))
+}
+
+// find find the bounded matches of the regexp which are valid
+func find(kind, text string, re *regexp.Regexp, valid func(string) bool) (spans []Span) {
+	for _, loc := range re.FindAllStringIndex(text, -1) {
+		s := text[loc[0]:loc[1]]
+		if bounded(text, loc[0], loc[1]) && (valid == nil || valid(s)) {
+			spans = append(spans, Span{Kind: kind, Text: s, Start: loc[0], End: loc[1]})
+		}
+	}
+	return
+}
+
+// findNumber find the numbers of the anchored regexp at every start,
+// only the bytes of the number, such as the digits, are the boundaries,
+// so the "tel13812345678" is found
+func findNumber(kind, text string, re *regexp.Regexp,
+	part func(b byte) bool, valid func(string) bool) (spans []Span) {
+	for i := 0; i < len(text); i++ {
+		if i > 0 && part(text[i-1]) {
+			continue
+		}
+
+		loc := re.FindStringIndex(text[i:])
+		if loc == nil {
+			continue
+		}
+
+		end := i + loc[1]
+		s := text[i:end]
+		if (end == len(text) || !part(text[end])) && (valid == nil || valid(s)) {
+			spans = append(spans, Span{Kind: kind, Text: s, Start: i, End: end})
+			i = end - 1
+		}
+	}
+	return
+}
+
+var (
+	idWeights = []int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+	idChecks  = "10X98765432"
+)
+
+// ValidIDCard check the 18 digits Chinese ID card number
+// by the birth date and the checksum
+func ValidIDCard(id string) bool {
+	if len(id) != 18 {
+		return false
+	}
+
+	month := (id[10]-'0')*10 + id[11] - '0'
+	day := (id[12]-'0')*10 + id[13] - '0'
+	if month < 1 || month > 12 || day < 1 || day > 31 {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < 17; i++ {
+		if id[i] < '0' || id[i] > '9' {
+			return false
+		}
+		sum += int(id[i]-'0') * idWeights[i]
+	}
+
+	return idChecks[sum%11] == id[17] || (id[17] == 'x' && idChecks[sum%11] == 'X')
+}
+
+// Luhn check the card number by the Luhn algorithm,
+// the spaces and dashes are ignored
+func Luhn(number string) bool {
+	sum, n := 0, 0
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		if c < '0' || c > '9' {
+			return false
+		}
+
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+
+	return n > 0 && sum%10 == 0
+}
+
+// DetectIDCard detect the Chinese ID card numbers with the checksum
+func DetectIDCard(text string) []Span {
+	return findNumber(IDCard, text, reIDCard, isDigit, ValidIDCard)
+}
+
+// DetectPhone detect the Chinese mobile numbers
+func DetectPhone(text string) []Span {
+	return findNumber(Phone, text, rePhone, isDigit, nil)
+}
+
+// DetectBankCard detect the 16 to 19 digits bank card numbers
+// passing the Luhn check, the digit groups can be separated by
+// a space or a dash, every group start and end is tried
+func DetectBankCard(text string) (spans []Span) {
+	for i := 0; i < len(text); i++ {
+		if !isDigit(text[i]) || (i > 0 && isDigit(text[i-1])) {
+			continue
+		}
+
+		// the ends of the digit groups with 16 to 19 digits
+		var ends []int
+		n, j := 0, i
+		for j < len(text) && n <= 19 {
+			if isDigit(text[j]) {
+				n++
+				j++
+				if n >= 16 && n <= 19 && (j == len(text) || !isDigit(text[j])) {
+					ends = append(ends, j)
+				}
+				continue
+			}
+
+			if (text[j] == ' ' || text[j] == '-') && j+1 < len(text) && isDigit(text[j+1]) {
+				j++
+				continue
+			}
+			break
+		}
+
+		for k := len(ends) - 1; k >= 0; k-- {
+			if Luhn(text[i:ends[k]]) {
+				spans = append(spans, Span{Kind: BankCard, Text: text[i:ends[k]],
+					Start: i, End: ends[k]})
+				i = ends[k] - 1
+				break
+			}
+		}
+	}
+	return
+}
+
+// DetectEmail detect the emails
+func DetectEmail(text string) []Span {
+	return find(Email, text, reEmail, nil)
+}
+
+// DetectIP detect the IPv4 and IPv6 addresses
+func DetectIP(text string) []Span {
+	valid := func(s string) bool {
+		return net.ParseIP(s) != nil
+	}
+
+	spans := findNumber(IP, text, reIPv4, func(b byte) bool {
+		return isDigit(b) || b == '.'
+	}, valid)
+	for _, s := range find(IP, text, reIPv6, valid) {
+		if strings.Count(s.Text, ":") >= 2 {
+			spans = append(spans, s)
+		}
+	}
+	return spans
+}
+
+// detectNames detect the person names by the ner
+func (m *Masker) detectNames(text string) (spans []Span) {
+	for _, e := range m.opt.Names.Recognize(text) {
+		if e.Type == ner.Person {
+			spans = append(spans, Span{Kind: Name, Text: e.Text, Start: e.Start, End: e.End})
+		}
+	}
+	return
+}
@@ -0,0 +1,113 @@
+package pii
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/go-ego/gse"
+	"github.com/go-ego/gse/ner"
+	"github.com/vcaesar/tt"
+)
+
+var reQQ = regexp.MustCompile(`[1-9]\d{4,10}`)
+
+func TestValid(t *testing.T) {
+	tt.True(t, ValidIDCard("11010519491231002X"))
+	tt.False(t, ValidIDCard("110105194912310021"))
+	tt.False(t, ValidIDCard("110105194913310021"))
+
+	tt.True(t, Luhn("6222021234567890128"))
+	tt.True(t, Luhn("6222 0212 3456 7890 128"))
+	tt.False(t, Luhn("6222021234567890123"))
+}
+
+func TestDetect(t *testing.T) {
+	m := New()
+	text := "身份证11010519491231002X，电话+86 138-1234-5678，卡号6222 0212 3456 7890 128，" +
+		"邮箱zhang.san@example.com，登录IP 192.168.1.10 和 fe80::1，订单号20240305123456789"
+	spans := m.Detect(text)
+	tt.Equal(t, 6, len(spans))
+
+	kinds := []string{IDCard, Phone, BankCard, Email, IP, IP}
+	for i, s := range spans {
+		tt.Equal(t, kinds[i], s.Kind)
+		tt.Equal(t, s.Text, text[s.Start:s.End])
+	}
+	tt.Equal(t, "+86 138-1234-5678", spans[1].Text)
+	tt.Equal(t, "fe80::1", spans[5].Text)
+
+	tt.Equal(t, 0, len(m.Detect("时间是12:30:45，版本1.2.3")))
+}
+
+func TestMask(t *testing.T) {
+	m := New(Options{Strategy: Partial, Strategies: map[string]Strategy{Email: Redact}})
+	text, spans := m.Mask("手机13812345678，邮箱a@b.cn，卡6222021234567890128，IP 10.0.12.7")
+	tt.Equal(t, "手机138****5678，邮箱[EMAIL]，卡6222***********0128，IP 10.0.*.*", text)
+	tt.Equal(t, 4, len(spans))
+	tt.Equal(t, "138****5678", spans[0].Masked)
+
+	tt.Equal(t, "+86 138-****-5678", m.partial(Span{Kind: Phone, Text: "+86 138-1234-5678"}))
+	tt.Equal(t, "110105********002X", m.partial(Span{Kind: IDCard, Text: "11010519491231002X"}))
+
+	h := New(Options{Strategy: Hash, Key: []byte("secret")})
+	t1, _ := h.Mask("13812345678")
+	t2, _ := h.Mask("call 13812345678")
+	tt.Equal(t, 20, len(t1))
+	tt.Equal(t, "call "+t1, t2)
+
+	h1 := New(Options{Strategy: Hash, Key: []byte("other")})
+	t4, _ := h1.Mask("13812345678")
+	tt.NotEqual(t, t1, t4)
+
+	// the Hash without the key is the Redact
+	h2 := New(Options{Strategy: Hash})
+	t5, _ := h2.Mask("13812345678")
+	tt.Equal(t, "[PHONE]", t5)
+
+	r := New(Options{Kinds: []string{Phone}})
+	t3, _ := r.Mask("a@b.cn 13812345678")
+	tt.Equal(t, "a@b.cn [PHONE]", t3)
+
+	r.Add(func(text string) []Span {
+		return find("qq", text, reQQ, nil)
+	})
+	t3, _ = r.Mask("QQ 123456789")
+	tt.Equal(t, "QQ [QQ]", t3)
+}
+
+func TestNames(t *testing.T) {
+	var seg gse.Segmenter
+	seg.SkipLog = true
+	seg.LoadDictStr(`的 1000 uj
+王 100 nr
+小明 100 nr
+电话 100 n
+是 1000 v`)
+
+	var rec ner.Recognizer
+	rec.WithGse(seg)
+	m := New(Options{Names: &rec, Strategy: Partial})
+	text, spans := m.Mask("王小明的电话是13812345678")
+	tt.Equal(t, "王**的电话是138****5678", text)
+	tt.Equal(t, Name, spans[0].Kind)
+	tt.Equal(t, 0, spans[0].Start)
+}
+
+func TestAdjacentNumbers(t *testing.T) {
+	m := New()
+	for _, text := range []string{
+		"手机13812345678 6222021234567890128",
+		"订单1234 6222021234567890128",
+	} {
+		spans := m.Detect(text)
+		last := spans[len(spans)-1]
+		tt.Equal(t, BankCard, last.Kind)
+		tt.Equal(t, "6222021234567890128", last.Text)
+	}
+
+	spans := m.Detect("tel13812345678, ip192.168.1.1")
+	tt.Equal(t, 2, len(spans))
+	tt.Equal(t, "13812345678", spans[0].Text)
+	tt.Equal(t, "192.168.1.1", spans[1].Text)
+	tt.Equal(t, 0, len(m.Detect("138123456789")))
+}
@@ -49,6 +49,9 @@ type Segmenter struct {
 	// SkipLog set skip log print
 	SkipLog bool
 	MoreLog bool
+	// Logger set the structured logger, such as *slog.Logger,
+	// use the standard log package with SkipLog and MoreLog if it is nil
+	Logger Logger
 
 	// SkipPos skip PosStr pos
 	SkipPos bool
@@ -56,6 +59,27 @@ type Segmenter struct {
 	NotStop bool
 	// StopWordMap the stop word map
 	StopWordMap map[string]bool
+
+	// Synonym emit the synonyms in the search mode, such as CutSearch,
+	// the synonym token shares the offsets of the source token, and the
+	// Position of them is the index of the source token in the segments
+	Synonym bool
+	// SynonymMap the synonym map, the text to its synonyms
+	SynonymMap map[string][]string
+
+	// AbbrMap the abbreviations not ending the sentence by ".",
+	// such as "dr", use the DefaultAbbr if it is nil
+	AbbrMap map[string]bool
+	// SentenceSplit the hook of the sentence boundary at the bytes end
+	// of the text, return false to not split the sentence there
+	SentenceSplit func(text string, end int) bool
+
+	// OOVCut cut the out of vocabulary text instead of the HMM,
+	// such as the rnn.Model.Cut
+	OOVCut func(text string) []string
+
+	// layers the dictionary layers sorted by priority
+	layers []*Layer
 }
 
 // jumper this structure is used to record information
@@ -85,7 +109,12 @@ func (seg *Segmenter) ModeSegment(bytes []byte, searchMode ...bool) []Segment {
 		mode = searchMode[0]
 	}
 
-	return seg.internalSegment(bytes, mode)
+	segs := seg.internalSegment(bytes, mode)
+	if mode && seg.Synonym {
+		return seg.expandSynonyms(segs)
+	}
+
+	return segs
 }
 
 func (seg *Segmenter) internalSegment(bytes []byte, searchMode bool) []Segment {
@@ -118,7 +147,8 @@ func (seg *Segmenter) segmentWords(text []Text, searchMode bool) []Segment {
 		return nil
 	}
 
-	tokens := make([]*Token, seg.Dict.maxTokenLen)
+	maxLen := seg.maxTokenLen()
+	tokens := make([]*Token, maxLen)
 	for current := 0; current < len(text); current++ {
 		// find the shortest path of the previous token,
 		// to calculate the subsequent path values
@@ -132,8 +162,8 @@ func (seg *Segmenter) segmentWords(text []Text, searchMode bool) []Segment {
 		}
 
 		// find all the segments starting with this token
-		tx := text[current:minInt(current+seg.Dict.maxTokenLen, len(text))]
-		numTokens := seg.Dict.LookupTokens(tx, tokens)
+		tx := text[current:minInt(current+maxLen, len(text))]
+		numTokens := seg.lookupTokens(tx, tokens)
 
 		// Update the jump information at the end of the split word
 		// for all possible splits
@@ -0,0 +1,234 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package gse
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultAbbr the default abbreviations not ending the sentence
+var DefaultAbbr = []string{
+	"mr", "mrs", "ms", "dr", "prof", "sr", "jr", "st", "mt", "vs", "etc",
+	"e.g", "i.e", "cf", "al", "inc", "ltd", "co", "corp", "no", "vol",
+	"fig", "dept", "approx", "u.s", "u.k",
+}
+
+// Sentence the sentence of the text with the bytes offsets
+type Sentence struct {
+	Text string
+	// Start and End the bytes offsets of the sentence in the text
+	Start, End int
+}
+
+func isTerminator(r rune) bool {
+	switch r {
+	case '.', '!', '?', '。', '！', '？', '…', '．', '｡':
+		return true
+	}
+	return false
+}
+
+// isCloser the closing quotes and brackets after the sentence terminator
+func isCloser(r rune) bool {
+	switch r {
+	case '"', '\'', '”', '’', '」', '』', ')', '）', ']', '】', '》', '〉', '〕', '}', '｝':
+		return true
+	}
+	return false
+}
+
+// isContinuation the punctuation continuing the sentence, such as "……，"
+func isContinuation(r rune) bool {
+	switch r {
+	case ',', '，', '、', ';', '；', ':', '：':
+		return true
+	}
+	return false
+}
+
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r)
+}
+
+// AddAbbr add the abbreviations not ending the sentence, such as "Dr"
+func (seg *Segmenter) AddAbbr(abbr ...string) {
+	if seg.AbbrMap == nil {
+		seg.AbbrMap = make(map[string]bool)
+		for _, a := range DefaultAbbr {
+			seg.AbbrMap[a] = true
+		}
+	}
+
+	for _, a := range abbr {
+		seg.AbbrMap[strings.TrimSuffix(strings.ToLower(a), ".")] = true
+	}
+}
+
+// RemoveAbbr remove the abbreviations
+func (seg *Segmenter) RemoveAbbr(abbr ...string) {
+	if seg.AbbrMap == nil {
+		seg.AddAbbr()
+	}
+
+	for _, a := range abbr {
+		delete(seg.AbbrMap, strings.TrimSuffix(strings.ToLower(a), "."))
+	}
+}
+
+// isAbbr the word before the "." at i is the abbreviation or initial
+func (seg *Segmenter) isAbbr(text string, i int) bool {
+	k := i
+	for k > 0 {
+		r, size := utf8.DecodeLastRuneInString(text[:k])
+		if !unicode.IsLetter(r) && r != '.' {
+			break
+		}
+		k -= size
+	}
+
+	word := text[k:i]
+	if word == "" {
+		return false
+	}
+
+	// the initial, such as "J. K. Rowling"
+	r, size := utf8.DecodeRuneInString(word)
+	if size == len(word) && unicode.IsUpper(r) {
+		return true
+	}
+
+	word = strings.ToLower(word)
+	if seg.AbbrMap == nil {
+		for _, a := range DefaultAbbr {
+			if a == word {
+				return true
+			}
+		}
+		return false
+	}
+
+	return seg.AbbrMap[word]
+}
+
+// isBoundary the terminators text[i:j] end the sentence
+func (seg *Segmenter) isBoundary(text string, i, j int, closed bool) bool {
+	if j >= len(text) {
+		return true
+	}
+
+	next, _ := utf8.DecodeRuneInString(text[j:])
+	if isContinuation(next) {
+		return false
+	}
+
+	// the Japanese quote followed by the particle, such as "「はい。」と"
+	if closed && unicode.Is(unicode.Hiragana, next) {
+		return false
+	}
+
+	group := text[i:j]
+	// the ellipsis in the sentence, such as "好……那"
+	if strings.Trim(group, "….") == "" && group != "." && !unicode.IsSpace(next) {
+		return false
+	}
+
+	if strings.IndexFunc(group, func(r rune) bool {
+		return r >= utf8.RuneSelf && isTerminator(r)
+	}) >= 0 {
+		// the full width terminators, such as "。" and "！"
+		return true
+	}
+
+	if !unicode.IsSpace(next) {
+		// the decimal, domain name and so on, such as "3.14"
+		return isCJK(next)
+	}
+
+	if group == "." && seg.isAbbr(text, i) {
+		return false
+	}
+
+	after := strings.TrimLeftFunc(text[j:], unicode.IsSpace)
+	r, _ := utf8.DecodeRuneInString(after)
+	return !unicode.IsLower(r)
+}
+
+// Sentences split the text to the sentences with the bytes offsets,
+// the sentence ends with the terminators of Chinese, Japanese and English,
+// the closing quotes and brackets, and the new line.
+//
+// The decimals, abbreviations (see AddAbbr) and ellipsis in the sentence
+// are not the end, use the SentenceSplit hook to customize it.
+func (seg *Segmenter) Sentences(text string) (sentences []Sentence) {
+	start := 0
+	add := func(end int) {
+		s := text[start:end]
+		trimmed := strings.TrimLeftFunc(s, unicode.IsSpace)
+		begin := start + len(s) - len(trimmed)
+		trimmed = strings.TrimRightFunc(trimmed, unicode.IsSpace)
+
+		if trimmed != "" {
+			sentences = append(sentences,
+				Sentence{Text: trimmed, Start: begin, End: begin + len(trimmed)})
+		}
+		start = end
+	}
+
+	i := 0
+	for i < len(text) {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if r == '\n' {
+			add(i)
+			i += size
+			continue
+		}
+
+		if !isTerminator(r) {
+			i += size
+			continue
+		}
+
+		j := i
+		for j < len(text) {
+			r, size = utf8.DecodeRuneInString(text[j:])
+			if !isTerminator(r) {
+				break
+			}
+			j += size
+		}
+
+		closed := false
+		for j < len(text) {
+			r, size = utf8.DecodeRuneInString(text[j:])
+			if !isCloser(r) {
+				break
+			}
+			j += size
+			closed = true
+		}
+
+		if seg.isBoundary(text, i, j, closed) &&
+			(seg.SentenceSplit == nil || seg.SentenceSplit(text, j)) {
+			add(j)
+		}
+		i = j
+	}
+	add(len(text))
+
+	return
+}
@@ -0,0 +1,57 @@
+package gse
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vcaesar/tt"
+)
+
+func sentenceTexts(ss []Sentence) []string {
+	texts := make([]string, len(ss))
+	for i, s := range ss {
+		texts[i] = s.Text
+	}
+	return texts
+}
+
+func TestSentences(t *testing.T) {
+	var seg1 Segmenter
+
+	text := "他说：“今天天气很好。”我们出去吧！真的吗？？好……那走吧，圆周率是3.14。"
+	ss := seg1.Sentences(text)
+	tt.Equal(t, "[他说：“今天天气很好。” 我们出去吧！ 真的吗？？ 好……那走吧，圆周率是3.14。]",
+		sentenceTexts(ss))
+	for _, s := range ss {
+		tt.Equal(t, s.Text, text[s.Start:s.End])
+	}
+
+	text = "Dr. Smith paid $3.50 for it, i.e. a bargain. J. K. Rowling wrote it!  " +
+		"\"Really?\" she asked. Wait... what happened? See example.com now."
+	ss = seg1.Sentences(text)
+	tt.Equal(t, 5, len(ss))
+	tt.Equal(t, "Dr. Smith paid $3.50 for it, i.e. a bargain.", ss[0].Text)
+	tt.Equal(t, "J. K. Rowling wrote it!", ss[1].Text)
+	tt.Equal(t, "\"Really?\" she asked.", ss[2].Text)
+	tt.Equal(t, "Wait... what happened?", ss[3].Text)
+	tt.Equal(t, "See example.com now.", ss[4].Text)
+	tt.Equal(t, 70, ss[2].Start)
+
+	ss = seg1.Sentences("「こんにちは。」と言った。今日は晴れです！\n次の行")
+	tt.Equal(t, "[「こんにちは。」と言った。 今日は晴れです！ 次の行]", sentenceTexts(ss))
+
+	text = "Prof. Lee and Gen. Park met. Then they left."
+	tt.Equal(t, 3, len(seg1.Sentences(text)))
+	seg1.AddAbbr("Gen.")
+	tt.Equal(t, 2, len(seg1.Sentences(text)))
+	seg1.RemoveAbbr("prof")
+	tt.Equal(t, 3, len(seg1.Sentences(text)))
+
+	seg1.SentenceSplit = func(text string, end int) bool {
+		return !strings.HasSuffix(text[:end], "met.")
+	}
+	tt.Equal(t, "[Prof. Lee and Gen. Park met. Then they left.]",
+		sentenceTexts(seg1.Sentences(text)))
+
+	tt.Equal(t, 0, len(seg1.Sentences(" \n ")))
+}
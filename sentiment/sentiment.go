@@ -0,0 +1,416 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+/*
+Package sentiment is the lexicon based sentiment analysis of the gse Pos,
+with the negation words, the degree adverbs and the contrastive conjunctions:
+
+	a := sentiment.New(&seg)
+	err := a.LoadLexicon("polarity.txt")
+	result := a.Analyze("这个手机很好，但是电池不耐用")
+
+The lexicon words are added to the segmenter dictionary, so they are
+not cut apart, and the domain words added by seg.AddToken can be scored
+by a.AddWord.
+*/
+package sentiment
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/go-ego/gse"
+)
+
+var (
+	// DefaultNegation the default negation words
+	DefaultNegation = []string{
+		"不", "没", "没有", "无", "非", "别", "未", "莫", "勿", "不曾",
+		"未必", "并非", "绝不", "毫不", "从不", "不要", "甭",
+	}
+
+	// DefaultDegree the default degree adverbs with the multiplier
+	DefaultDegree = map[string]float64{
+		"最": 2, "极": 2, "极其": 2, "超": 1.8, "太": 1.8, "非常": 1.8,
+		"特别": 1.8, "十分": 1.8, "相当": 1.5, "很": 1.5, "挺": 1.3,
+		"更": 1.3, "更加": 1.3, "比较": 1.2, "较": 1.2, "还": 1.1,
+		"有点": 0.8, "有些": 0.8, "稍": 0.7, "稍微": 0.7, "略": 0.7,
+	}
+
+	// DefaultContrast the default contrastive conjunctions
+	DefaultContrast = []string{
+		"但", "但是", "可是", "然而", "不过", "却", "只是",
+	}
+)
+
+// Options the sentiment options
+type Options struct {
+	// Negation the negated score is -Negation * score, default is 1
+	Negation float64
+	// NegDegree the factor of the negation before the degree adverb,
+	// such as "不很好" is weaker than "很不好", default is 0.5
+	NegDegree float64
+	// ContrastBefore and ContrastAfter the weights of the words
+	// before and after the contrastive conjunction, default is 0.5 and 1.5
+	ContrastBefore, ContrastAfter float64
+	// Window the max words between the modifier and the sentiment word,
+	// default is 3
+	Window int
+	// Freq the frequency of the lexicon words added to the segmenter,
+	// default is 100
+	Freq float64
+}
+
+// Analyzer the lexicon based sentiment analyzer
+type Analyzer struct {
+	seg *gse.Segmenter
+	opt Options
+
+	// Polarity the sentiment words with the intensity,
+	// positive is greater than 0
+	Polarity map[string]float64
+	Negation map[string]bool
+	Degree   map[string]float64
+	Contrast map[string]bool
+}
+
+// Evidence the scored sentiment word
+type Evidence struct {
+	Word string
+	// Start and End the bytes offsets of the word in the text
+	Start, End int
+	// Base the lexicon score, Score the modified score
+	Base, Score float64
+	// Modifiers the negation and degree words of the sentiment word
+	Modifiers []string
+}
+
+// Sentence the sentence sentiment
+type Sentence struct {
+	Text string
+	// Start and End the bytes offsets of the sentence in the text
+	Start, End int
+	Score      float64
+	Evidence   []Evidence
+}
+
+// Result the document sentiment
+type Result struct {
+	Score float64
+	// Positive and Negative the sums of the positive and negative scores
+	Positive, Negative float64
+	Sentences          []Sentence
+}
+
+// Label return the "positive", "negative" or "neutral" of the score
+func (r Result) Label() string {
+	switch {
+	case r.Score > 0:
+		return "positive"
+	case r.Score < 0:
+		return "negative"
+	}
+	return "neutral"
+}
+
+// New create a new Analyzer with the segmenter and the default
+// negation words, degree adverbs and contrastive conjunctions
+func New(seg *gse.Segmenter, opts ...Options) *Analyzer {
+	var opt Options
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	if opt.Negation <= 0 {
+		opt.Negation = 1
+	}
+	if opt.NegDegree <= 0 {
+		opt.NegDegree = 0.5
+	}
+	if opt.ContrastBefore <= 0 {
+		opt.ContrastBefore = 0.5
+	}
+	if opt.ContrastAfter <= 0 {
+		opt.ContrastAfter = 1.5
+	}
+	if opt.Window <= 0 {
+		opt.Window = 3
+	}
+	if opt.Freq <= 0 {
+		opt.Freq = 100
+	}
+
+	a := &Analyzer{seg: seg, opt: opt,
+		Polarity: make(map[string]float64),
+		Negation: make(map[string]bool),
+		Degree:   make(map[string]float64),
+		Contrast: make(map[string]bool),
+	}
+
+	add := false
+	for _, w := range DefaultNegation {
+		a.Negation[w] = true
+		add = a.addToken(w, "d") || add
+	}
+	for w, d := range DefaultDegree {
+		a.Degree[w] = d
+		add = a.addToken(w, "d") || add
+	}
+	for _, w := range DefaultContrast {
+		a.Contrast[w] = true
+		add = a.addToken(w, "c") || add
+	}
+	if add {
+		seg.CalcToken()
+	}
+
+	return a
+}
+
+// addToken add the word to the segmenter if it is not in the dictionary,
+// return true if added
+func (a *Analyzer) addToken(word, pos string) bool {
+	// the prefix of the dictionary words is found with the freq 0
+	if freq, _, ok := a.seg.Find(word); ok && freq > 0 {
+		return false
+	}
+
+	return a.seg.AddToken(word, a.opt.Freq, pos) == nil
+}
+
+// AddWord add the sentiment word with the intensity
+func (a *Analyzer) AddWord(word string, score float64) {
+	a.Polarity[word] = score
+	if a.addToken(word, "a") {
+		a.seg.CalcToken()
+	}
+}
+
+// AddNegation add the negation words
+func (a *Analyzer) AddNegation(words ...string) {
+	add := false
+	for _, w := range words {
+		a.Negation[w] = true
+		add = a.addToken(w, "d") || add
+	}
+	if add {
+		a.seg.CalcToken()
+	}
+}
+
+// AddDegree add the degree adverb with the multiplier
+func (a *Analyzer) AddDegree(word string, multiplier float64) {
+	a.Degree[word] = multiplier
+	if a.addToken(word, "d") {
+		a.seg.CalcToken()
+	}
+}
+
+// AddContrast add the contrastive conjunctions
+func (a *Analyzer) AddContrast(words ...string) {
+	add := false
+	for _, w := range words {
+		a.Contrast[w] = true
+		add = a.addToken(w, "c") || add
+	}
+	if add {
+		a.seg.CalcToken()
+	}
+}
+
+// ReadLexicon read the polarity lexicon of the "word score" lines,
+// such as "好 1" and "糟糕 -2", the "#" lines are comments
+func (a *Analyzer) ReadLexicon(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	add := false
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) < 2 {
+			return fmt.Errorf("sentiment: line %d: not the word score format", line)
+		}
+		score, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return fmt.Errorf("sentiment: line %d: %v", line, err)
+		}
+
+		a.Polarity[fields[0]] = score
+		add = a.addToken(fields[0], "a") || add
+	}
+
+	if add {
+		a.seg.CalcToken()
+	}
+	return scanner.Err()
+}
+
+// LoadLexiconStr load the polarity lexicon from the string
+func (a *Analyzer) LoadLexiconStr(str string) error {
+	return a.ReadLexicon(strings.NewReader(str))
+}
+
+// LoadLexicon load the polarity lexicon files
+func (a *Analyzer) LoadLexicon(files ...string) error {
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+
+		err = a.ReadLexicon(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isPunct(word string) bool {
+	return strings.IndexFunc(word, func(r rune) bool {
+		return !unicode.IsPunct(r) && !unicode.IsSymbol(r)
+	}) < 0 && strings.TrimSpace(word) != ""
+}
+
+// modifier the pending negation and degree modifiers
+type modifier struct {
+	neg       int
+	degree    float64
+	negDegree bool
+	words     []string
+	gap       int
+}
+
+func (m *modifier) reset() {
+	*m = modifier{degree: 1}
+}
+
+// ScorePos score the Pos words as a sentence,
+// the offsets are of the words concatenated
+func (a *Analyzer) ScorePos(words []gse.SegPos) Sentence {
+	var b strings.Builder
+	for _, w := range words {
+		b.WriteString(w.Text)
+	}
+
+	s := Sentence{Text: b.String()}
+	s.End = len(s.Text)
+	a.score(&s, words, 0)
+	return s
+}
+
+// score score the words of the sentence, the text offset is base
+func (a *Analyzer) score(s *Sentence, words []gse.SegPos, base int) {
+	var m modifier
+	m.reset()
+	contrast := false
+
+	cur := 0
+	for _, w := range words {
+		start := cur
+		if i := strings.Index(s.Text[cur:], w.Text); i >= 0 {
+			start = cur + i
+			cur = start + len(w.Text)
+		}
+
+		word := w.Text
+		if m.gap > a.opt.Window {
+			m.reset()
+		}
+
+		if score, ok := a.Polarity[word]; ok {
+			v := score * m.degree
+			if m.neg%2 == 1 {
+				v = -v * a.opt.Negation
+				if m.negDegree {
+					v *= a.opt.NegDegree
+				}
+			}
+			if contrast {
+				v *= a.opt.ContrastAfter
+			}
+
+			s.Evidence = append(s.Evidence, Evidence{Word: word,
+				Start: base + start, End: base + start + len(word),
+				Base: score, Score: v, Modifiers: m.words})
+			m.reset()
+			continue
+		}
+
+		switch {
+		case a.Contrast[word]:
+			for i := range s.Evidence {
+				s.Evidence[i].Score *= a.opt.ContrastBefore
+			}
+			contrast = true
+			m.reset()
+		case a.Negation[word]:
+			m.neg++
+			m.words = append(m.words, word)
+			m.gap = 0
+		case a.Degree[word] > 0:
+			m.degree *= a.Degree[word]
+			if m.neg > 0 {
+				m.negDegree = true
+			}
+			m.words = append(m.words, word)
+			m.gap = 0
+		case isPunct(word):
+			m.reset()
+		case strings.TrimSpace(word) != "":
+			m.gap++
+		}
+	}
+
+	for _, e := range s.Evidence {
+		s.Score += e.Score
+	}
+}
+
+// Analyze analyze the sentiment of the text by the sentences
+func (a *Analyzer) Analyze(text string) (r Result) {
+	for _, sen := range a.seg.Sentences(text) {
+		s := Sentence{Text: sen.Text, Start: sen.Start, End: sen.End}
+		a.score(&s, a.seg.Pos(sen.Text), sen.Start)
+
+		for _, e := range s.Evidence {
+			if e.Score > 0 {
+				r.Positive += e.Score
+			} else {
+				r.Negative -= e.Score
+			}
+		}
+		r.Score += s.Score
+		r.Sentences = append(r.Sentences, s)
+	}
+
+	return
+}
+
+// Score return the sentiment score of the text
+func (a *Analyzer) Score(text string) float64 {
+	return a.Analyze(text).Score
+}
@@ -0,0 +1,100 @@
+package sentiment
+
+import (
+	"testing"
+
+	"github.com/go-ego/gse"
+	"github.com/vcaesar/tt"
+)
+
+const lexicon = `# polarity
+好 1
+喜欢 2
+漂亮 1.5
+糟糕 -2
+失望 -1.5
+`
+
+func newAnalyzer() (*gse.Segmenter, *Analyzer) {
+	var seg gse.Segmenter
+	seg.SkipLog = true
+	seg.LoadDictStr(`的 1000 uj
+这个 500 r
+手机 100 n
+电池 100 n
+外观 100 n
+是 1000 v
+我 1000 r`)
+
+	a := New(&seg)
+	a.LoadLexiconStr(lexicon)
+	return &seg, a
+}
+
+func TestScore(t *testing.T) {
+	_, a := newAnalyzer()
+	tt.Equal(t, 1.5, a.Score("这个手机很好"))
+	tt.Equal(t, -1, a.Score("这个手机不好"))
+	tt.Equal(t, -1.5, a.Score("手机很不好"))
+	tt.Equal(t, -0.75, a.Score("手机不是很好"))
+	tt.Equal(t, 2, a.Score("没有不喜欢"))
+	tt.Equal(t, 0, a.Score("这个手机"))
+
+	r := a.Analyze("外观漂亮，但是电池糟糕")
+	tt.Equal(t, -2.25, r.Score)
+	tt.Equal(t, "negative", r.Label())
+	tt.Equal(t, 0.75, r.Positive)
+	tt.Equal(t, 3, r.Negative)
+}
+
+func TestEvidence(t *testing.T) {
+	_, a := newAnalyzer()
+	text := "我喜欢这个手机。电池非常失望!"
+	r := a.Analyze(text)
+	tt.Equal(t, 2, len(r.Sentences))
+	tt.Equal(t, "negative", r.Label())
+
+	e := r.Sentences[1].Evidence[0]
+	tt.Equal(t, "失望", e.Word)
+	tt.Equal(t, "失望", text[e.Start:e.End])
+	tt.Equal(t, -2.7, e.Score)
+	tt.Equal(t, "[非常]", e.Modifiers)
+	tt.Equal(t, "我喜欢这个手机。", r.Sentences[0].Text)
+}
+
+func TestDomainWord(t *testing.T) {
+	seg, a := newAnalyzer()
+	tt.Equal(t, 0, a.Score("电池给力"))
+
+	a.AddWord("给力", 2)
+	tt.Equal(t, "[电池 给力]", seg.Cut("电池给力"))
+	tt.Equal(t, 2, a.Score("电池给力"))
+
+	seg.AddToken("续航", 100, "n")
+	s := a.ScorePos(seg.Pos("续航太给力"))
+	tt.Equal(t, 3.6, s.Score)
+	tt.Equal(t, 9, s.Evidence[0].Start)
+
+	a.AddNegation("不太")
+	tt.Equal(t, -2, a.Score("不太给力"))
+	a.AddDegree("超级", 2)
+	tt.Equal(t, 4, a.Score("超级给力"))
+	a.AddContrast("可惜")
+	tt.Equal(t, 3.5, a.Score("好，可惜给力"))
+
+	tt.NotNil(t, a.LoadLexiconStr("坏"))
+}
+
+func TestPrefixWord(t *testing.T) {
+	seg, a := newAnalyzer()
+	seg.AddToken("给力十足", 100, "a")
+	seg.AddToken("超级市场", 100, "n")
+	seg.CalcToken()
+
+	a.AddWord("给力", 2)
+	tt.Equal(t, "[电池 给力]", seg.Cut("电池给力"))
+	tt.Equal(t, 2, a.Score("电池给力"))
+
+	a.AddDegree("超级", 2)
+	tt.Equal(t, 4, a.Score("超级给力"))
+}
@@ -16,7 +16,6 @@ package gse
 
 import (
 	"bufio"
-	"log"
 	"os"
 	"path"
 	"strings"
@@ -56,13 +55,11 @@ func (seg *Segmenter) LoadStop(files ...string) error {
 	}
 
 	for i := 0; i < len(name); i++ {
-		if !seg.SkipLog {
-			log.Printf("Load the stop word dictionary: \"%s\" ", name[i])
-		}
+		seg.Log().Info("load the stop word dictionary", "file", name[i])
 
 		file, err := os.Open(name[i])
 		if err != nil {
-			log.Printf("Could not load dictionaries: \"%s\", %v \n", name[i], err)
+			seg.Log().Error("could not load dictionaries", "file", name[i], "error", err)
 			return err
 		}
 		defer file.Close()
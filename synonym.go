@@ -0,0 +1,167 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package gse
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// normText normalize the text like the segment token text
+func (seg *Segmenter) normText(text string) string {
+	return Join(seg.SplitTextToWords([]byte(strings.TrimSpace(text))))
+}
+
+// AddSynonym add the one-way synonyms of the text,
+// such as: 手机 => 移动电话
+func (seg *Segmenter) AddSynonym(text string, synonyms ...string) {
+	if seg.SynonymMap == nil {
+		seg.SynonymMap = make(map[string][]string)
+	}
+
+	key := seg.normText(text)
+	if key == "" {
+		return
+	}
+
+	for _, s := range synonyms {
+		s = seg.normText(s)
+		if s == "" || s == key {
+			continue
+		}
+
+		exist := false
+		for _, v := range seg.SynonymMap[key] {
+			if v == s {
+				exist = true
+				break
+			}
+		}
+
+		if !exist {
+			seg.SynonymMap[key] = append(seg.SynonymMap[key], s)
+		}
+	}
+}
+
+// AddSynonymGroup add the two-way synonyms,
+// each text is the synonym of the others, such as: 电脑, 计算机
+func (seg *Segmenter) AddSynonymGroup(texts ...string) {
+	for _, text := range texts {
+		seg.AddSynonym(text, texts...)
+	}
+}
+
+// RemoveSynonym remove the synonyms of the text
+func (seg *Segmenter) RemoveSynonym(text string) {
+	delete(seg.SynonymMap, seg.normText(text))
+}
+
+// Synonyms return the synonyms of the text
+func (seg *Segmenter) Synonyms(text string) []string {
+	return seg.SynonymMap[seg.normText(text)]
+}
+
+// LoadSynonymStr load the synonym rules from the string, one rule for each line:
+//
+//	电脑, 计算机
+//	手机, 智能手机 => 移动电话
+//
+// The words separated by "," are two-way synonyms,
+// "=>" is the one-way rule from the left words to the right words,
+// the line starting with "#" is the comment.
+func (seg *Segmenter) LoadSynonymStr(dict string) {
+	for _, line := range strings.Split(dict, "\n") {
+		seg.addSynonymRule(line)
+	}
+}
+
+func (seg *Segmenter) addSynonymRule(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return
+	}
+
+	if i := strings.Index(line, "=>"); i >= 0 {
+		to := strings.Split(line[i+2:], ",")
+		for _, from := range strings.Split(line[:i], ",") {
+			seg.AddSynonym(from, to...)
+		}
+		return
+	}
+
+	seg.AddSynonymGroup(strings.Split(line, ",")...)
+}
+
+// LoadSynonym load the synonym dictionary files, see LoadSynonymStr for the format
+func (seg *Segmenter) LoadSynonym(files ...string) error {
+	for _, name := range files {
+		seg.Log().Info("load the synonym dictionary", "file", name)
+
+		file, err := os.Open(name)
+		if err != nil {
+			seg.Log().Error("could not load dictionaries", "file", name, "error", err)
+			return err
+		}
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			seg.addSynonymRule(scanner.Text())
+		}
+		file.Close()
+
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// synonymToken return the dictionary token of the synonym,
+// or a new token with the freq and pos of the source token
+func (seg *Segmenter) synonymToken(text string, source *Token) *Token {
+	if token, ok := seg.FindToken(text); ok {
+		return token
+	}
+
+	words := seg.SplitTextToWords([]byte(text))
+	return &Token{text: words, freq: source.freq, pos: source.pos}
+}
+
+// expandSynonyms add the synonym segments after the source segment,
+// sharing the same offsets, the Position is the index of the source
+func (seg *Segmenter) expandSynonyms(segs []Segment) []Segment {
+	if len(seg.SynonymMap) == 0 {
+		return segs
+	}
+
+	output := make([]Segment, 0, len(segs))
+	for i, s := range segs {
+		s.Position = i
+		output = append(output, s)
+		for _, syn := range seg.Synonyms(s.token.Text()) {
+			output = append(output, Segment{
+				start:    s.start,
+				end:      s.end,
+				Position: s.Position,
+				token:    seg.synonymToken(syn, s.token),
+			})
+		}
+	}
+
+	return output
+}
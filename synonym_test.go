@@ -0,0 +1,54 @@
+package gse
+
+import (
+	"testing"
+
+	"github.com/vcaesar/tt"
+)
+
+func TestSynonym(t *testing.T) {
+	var seg1 Segmenter
+	seg1.SkipLog = true
+	err := seg1.LoadDictStr("电脑 100 n\n计算机 100 n\n手机 100 n\n" +
+		"移动 50 v\n电话 50 n\n移动电话 20 n\n买 30 v\n新 30 a\n个人 40 n")
+	tt.Nil(t, err)
+
+	err = seg1.LoadSynonym("testdata/synonym.txt")
+	tt.Nil(t, err)
+	tt.Equal(t, "[计算机]", seg1.Synonyms("电脑"))
+	tt.Equal(t, "[电脑]", seg1.Synonyms("计算机"))
+	tt.Equal(t, "[移动电话]", seg1.Synonyms("手机"))
+	tt.Equal(t, "[]", seg1.Synonyms("移动电话"))
+	tt.Equal(t, "[个人电脑]", seg1.Synonyms("PC"))
+	tt.Equal(t, "[pc]", seg1.Synonyms("个人电脑"))
+
+	text := "买新手机和电脑"
+	tt.Equal(t, "[买 新 手机 和 电脑]", seg1.CutSearch(text))
+
+	seg1.Synonym = true
+	tt.Equal(t, "[买 新 手机 移动 电话 移动电话 和 电脑 计算机]", seg1.CutSearch(text))
+	tt.Equal(t, "[买 新 手机 移动电话 和 电脑 计算机]", seg1.CutSearch(text, false))
+	tt.Equal(t, "[买 新 手机 和 电脑]", seg1.Cut(text))
+
+	segs := seg1.ModeSegment([]byte(text), true)
+	tt.Equal(t, 7, len(segs))
+	tt.Equal(t, "移动电话", segs[3].Token().Text())
+	tt.Equal(t, "n", segs[3].Token().Pos())
+	tt.Equal(t, segs[2].Start(), segs[3].Start())
+	tt.Equal(t, segs[2].End(), segs[3].End())
+	tt.Equal(t, 2, segs[3].Position)
+	tt.Equal(t, 4, segs[6].Position)
+
+	seg1.AddSynonym("买", "购买")
+	segs = seg1.ModeSegment([]byte(text), true)
+	tt.Equal(t, "购买", segs[1].Token().Text())
+	tt.Equal(t, "v", segs[1].Token().Pos())
+	tt.Equal(t, 0, segs[1].Position)
+
+	tt.Equal(t, "[买 购买 pc 个人电脑]", seg1.CutSearch("买PC", false))
+	// the Position is only set with the synonyms of the search mode
+	tt.Equal(t, 0, seg1.Segment([]byte(text))[2].Position)
+
+	seg1.RemoveSynonym("买")
+	tt.Equal(t, "[]", seg1.Synonyms("买"))
+}
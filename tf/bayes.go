@@ -0,0 +1,139 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package tf
+
+import "math"
+
+// NaiveBayes the multinomial Naive Bayes classifier,
+// the feature values are the counts or the weights, such as the TF-IDF
+type NaiveBayes struct {
+	// Alpha the additive (Laplace) smoothing, default is 1
+	Alpha float64 `json:"alpha"`
+
+	Labels []string `json:"labels"`
+	// Docs the examples number of the label
+	Docs map[string]int `json:"docs"`
+	// Counts the feature values sum of the label
+	Counts map[string]Features `json:"counts"`
+	// Totals the all feature values sum of the label
+	Totals map[string]float64 `json:"totals"`
+	// Vocab the number of the distinct features
+	Vocab int `json:"vocab"`
+}
+
+// NewNaiveBayes create a new NaiveBayes with the smoothing alpha
+func NewNaiveBayes(alpha ...float64) *NaiveBayes {
+	nb := &NaiveBayes{Alpha: 1,
+		Docs:   make(map[string]int),
+		Counts: make(map[string]Features),
+		Totals: make(map[string]float64),
+	}
+	if len(alpha) > 0 && alpha[0] > 0 {
+		nb.Alpha = alpha[0]
+	}
+
+	return nb
+}
+
+// Fit train the model by the examples, it can be called incrementally
+func (nb *NaiveBayes) Fit(examples []Example) {
+	nb.Labels = labelsOf(examples, nb.Labels)
+	for _, e := range examples {
+		nb.Docs[e.Label]++
+		counts := nb.Counts[e.Label]
+		if counts == nil {
+			counts = make(Features)
+			nb.Counts[e.Label] = counts
+		}
+
+		for name, v := range e.Features {
+			if v <= 0 {
+				continue
+			}
+			counts[name] += v
+			nb.Totals[e.Label] += v
+		}
+	}
+
+	vocab := make(map[string]bool)
+	for _, counts := range nb.Counts {
+		for name := range counts {
+			vocab[name] = true
+		}
+	}
+	nb.Vocab = len(vocab)
+}
+
+// LogProbs return the unnormalized log probabilities of the labels,
+// in the order of the Labels, the unknown features are ignored,
+// the untrained model return the uniform zero logs
+func (nb *NaiveBayes) LogProbs(f Features) []float64 {
+	docs := 0
+	for _, n := range nb.Docs {
+		docs += n
+	}
+
+	logs := make([]float64, len(nb.Labels))
+	if docs == 0 {
+		return logs
+	}
+
+	for i, l := range nb.Labels {
+		lp := math.Log(float64(nb.Docs[l]) / float64(docs))
+		denom := math.Log(nb.Totals[l] + nb.Alpha*float64(nb.Vocab))
+		for name, v := range f {
+			if v <= 0 || !nb.known(name) {
+				continue
+			}
+			lp += v * (math.Log(nb.Counts[l][name]+nb.Alpha) - denom)
+		}
+		logs[i] = lp
+	}
+
+	return logs
+}
+
+func (nb *NaiveBayes) known(name string) bool {
+	for _, counts := range nb.Counts {
+		if _, ok := counts[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Predict return the labels with the probability, sorted by the probability
+func (nb *NaiveBayes) Predict(f Features) []Result {
+	probs := nb.LogProbs(f)
+	softmax(probs)
+	return results(nb.Labels, probs)
+}
+
+// softmax turn the log scores to the probabilities
+func softmax(v []float64) {
+	max := math.Inf(-1)
+	for _, x := range v {
+		max = math.Max(max, x)
+	}
+
+	sum := 0.0
+	for i, x := range v {
+		v[i] = math.Exp(x - max)
+		sum += v[i]
+	}
+	for i := range v {
+		v[i] /= sum
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package tf
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ClassReport the evaluation of a label
+type ClassReport struct {
+	Precision, Recall, F1 float64
+	// Support the gold number of the label
+	Support int
+}
+
+// Report the evaluation report
+type Report struct {
+	Accuracy float64
+	// MacroF1 the average F1 of the labels
+	MacroF1 float64
+	// Labels the sorted labels of the gold and predicted
+	Labels  []string
+	Classes map[string]ClassReport
+}
+
+// Evaluate evaluate the predicted labels by the gold labels
+func Evaluate(gold, pred []string) Report {
+	r := Report{Classes: make(map[string]ClassReport)}
+	tp := make(map[string]int)
+	predicted := make(map[string]int)
+	support := make(map[string]int)
+
+	right := 0
+	for i, g := range gold {
+		support[g]++
+		if i >= len(pred) {
+			continue
+		}
+
+		predicted[pred[i]]++
+		if pred[i] == g {
+			tp[g]++
+			right++
+		}
+	}
+	if len(gold) > 0 {
+		r.Accuracy = float64(right) / float64(len(gold))
+	}
+
+	seen := make(map[string]bool)
+	for _, labels := range []map[string]int{support, predicted} {
+		for l := range labels {
+			if !seen[l] {
+				seen[l] = true
+				r.Labels = append(r.Labels, l)
+			}
+		}
+	}
+	sort.Strings(r.Labels)
+
+	for _, l := range r.Labels {
+		c := ClassReport{Support: support[l]}
+		if predicted[l] > 0 {
+			c.Precision = float64(tp[l]) / float64(predicted[l])
+		}
+		if support[l] > 0 {
+			c.Recall = float64(tp[l]) / float64(support[l])
+		}
+		if c.Precision+c.Recall > 0 {
+			c.F1 = 2 * c.Precision * c.Recall / (c.Precision + c.Recall)
+		}
+
+		r.Classes[l] = c
+		r.MacroF1 += c.F1
+	}
+	if len(r.Labels) > 0 {
+		r.MacroF1 /= float64(len(r.Labels))
+	}
+
+	return r
+}
+
+// Test evaluate the model by the examples
+func Test(m Model, examples []Example) Report {
+	gold := make([]string, len(examples))
+	pred := make([]string, len(examples))
+	for i, e := range examples {
+		gold[i] = e.Label
+		pred[i] = Classify(m, e.Features)
+	}
+
+	return Evaluate(gold, pred)
+}
+
+// String return the report table
+func (r Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-12s %9s %9s %9s %9s\n", "label", "precision", "recall", "f1", "support")
+	for _, l := range r.Labels {
+		c := r.Classes[l]
+		fmt.Fprintf(&b, "%-12s %9.4f %9.4f %9.4f %9d\n", l, c.Precision, c.Recall, c.F1, c.Support)
+	}
+	fmt.Fprintf(&b, "accuracy %.4f, macro f1 %.4f\n", r.Accuracy, r.MacroF1)
+
+	return b.String()
+}
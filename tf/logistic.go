@@ -0,0 +1,179 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package tf
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// LogisticOptions the logistic regression training options
+type LogisticOptions struct {
+	// L2 the L2 regularization strength, default is 1e-4,
+	// set it to negative to disable the regularization
+	L2 float64
+	// LR the SGD learning rate, default is 0.1
+	LR float64
+	// Epochs the training epochs, default is 20
+	Epochs int
+	// Seed the random seed of the shuffle
+	Seed int64
+}
+
+// Logistic the L2-regularised (multinomial) logistic regression classifier
+type Logistic struct {
+	Opt LogisticOptions `json:"options"`
+
+	Labels []string `json:"labels"`
+	// Weights the feature weights of each label, in the order of the Labels
+	Weights map[string][]float64 `json:"weights"`
+	Bias    []float64            `json:"bias"`
+}
+
+// NewLogistic create a new Logistic
+func NewLogistic(opts ...LogisticOptions) *Logistic {
+	var opt LogisticOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	if opt.L2 < 0 {
+		opt.L2 = 0
+	} else if opt.L2 == 0 {
+		opt.L2 = 1e-4
+	}
+	if opt.LR <= 0 {
+		opt.LR = 0.1
+	}
+	if opt.Epochs <= 0 {
+		opt.Epochs = 20
+	}
+
+	return &Logistic{Opt: opt, Weights: make(map[string][]float64)}
+}
+
+// scores return the linear scores of the labels
+func (lr *Logistic) scores(f Features) []float64 {
+	s := make([]float64, len(lr.Labels))
+	copy(s, lr.Bias)
+	for name, v := range f {
+		if w, ok := lr.Weights[name]; ok {
+			for i := range w {
+				s[i] += w[i] * v
+			}
+		}
+	}
+
+	return s
+}
+
+// valid check the bias and the weights are the length of the Labels
+func (lr *Logistic) valid() error {
+	if len(lr.Bias) != len(lr.Labels) {
+		return fmt.Errorf("tf: the bias length %d is not the labels length %d",
+			len(lr.Bias), len(lr.Labels))
+	}
+
+	for name, w := range lr.Weights {
+		if len(w) != len(lr.Labels) {
+			return fmt.Errorf("tf: the weights length %d of %q is not the labels length %d",
+				len(w), name, len(lr.Labels))
+		}
+	}
+	return nil
+}
+
+// Fit train the model by the examples with the SGD,
+// it continues training if the model is trained
+func (lr *Logistic) Fit(examples []Example) {
+	lr.Train(examples)
+}
+
+// Train train the model by the examples, return the average loss of the last epoch
+func (lr *Logistic) Train(examples []Example) float64 {
+	labels := labelsOf(examples, lr.Labels)
+	if len(labels) != len(lr.Labels) {
+		// remap the weights to the new labels
+		index := make(map[string]int, len(labels))
+		for i, l := range labels {
+			index[l] = i
+		}
+
+		bias := make([]float64, len(labels))
+		for i, l := range lr.Labels {
+			bias[index[l]] = lr.Bias[i]
+		}
+		for name, w := range lr.Weights {
+			nw := make([]float64, len(labels))
+			for i, l := range lr.Labels {
+				nw[index[l]] = w[i]
+			}
+			lr.Weights[name] = nw
+		}
+		lr.Labels, lr.Bias = labels, bias
+	}
+
+	index := make(map[string]int, len(lr.Labels))
+	for i, l := range lr.Labels {
+		index[l] = i
+	}
+
+	order := rand.New(rand.NewSource(lr.Opt.Seed)).Perm(len(examples))
+	loss := 0.0
+	for epoch := 0; epoch < lr.Opt.Epochs; epoch++ {
+		loss = 0.0
+		// the learning rate decay
+		rate := lr.Opt.LR / (1 + 0.1*float64(epoch))
+
+		for _, k := range order {
+			e := examples[k]
+			probs := lr.scores(e.Features)
+			softmax(probs)
+			gold := index[e.Label]
+			loss -= math.Log(math.Max(probs[gold], 1e-12))
+
+			for i := range probs {
+				d := probs[i]
+				if i == gold {
+					d--
+				}
+				lr.Bias[i] -= rate * d
+
+				for name, v := range e.Features {
+					w := lr.Weights[name]
+					if w == nil {
+						w = make([]float64, len(lr.Labels))
+						lr.Weights[name] = w
+					}
+					w[i] -= rate * (d*v + lr.Opt.L2*w[i])
+				}
+			}
+		}
+
+		if len(examples) > 0 {
+			loss /= float64(len(examples))
+		}
+	}
+
+	return loss
+}
+
+// Predict return the labels with the probability, sorted by the probability
+func (lr *Logistic) Predict(f Features) []Result {
+	probs := lr.scores(f)
+	softmax(probs)
+	return results(lr.Labels, probs)
+}
@@ -1 +1,293 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+/*
+Package nlp is the text classification of the gse tokens,
+the bag-of-words or TF-IDF features with the tf models:
+
+	c := nlp.New(&seg, tf.NewNaiveBayes())
+	err := c.TrainFile("train.txt")
+	label := c.Classify("text")
+
+The labelled file is the "label<TAB>text" lines.
+*/
 package nlp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/go-ego/gse"
+	"github.com/go-ego/gse/tf"
+	"github.com/go-ego/gse/vector"
+)
+
+// Sample the text with the label
+type Sample struct {
+	Text  string
+	Label string
+}
+
+// ReadSamples read the "label<TAB>text" lines, the empty lines are skipped
+func ReadSamples(r io.Reader) (samples []Sample, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		i := strings.IndexByte(text, '\t')
+		if i <= 0 {
+			return nil, fmt.Errorf("nlp: line %d: not the label<TAB>text format", line)
+		}
+
+		samples = append(samples, Sample{
+			Label: strings.TrimSpace(text[:i]),
+			Text:  strings.TrimSpace(text[i+1:]),
+		})
+	}
+
+	return samples, scanner.Err()
+}
+
+// LoadSamples load the samples from the labelled file
+func LoadSamples(file string) ([]Sample, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadSamples(f)
+}
+
+// Options the featurization options
+type Options struct {
+	// TFIDF weight the term counts by the IDF of the training texts
+	TFIDF bool
+	// Binary use 1 instead of the term counts
+	Binary bool
+	// Norm normalize the features to the unit L2 norm
+	Norm bool
+	// NGram the maximum n of the word n-grams, default is 1
+	NGram int
+	// Stop remove the stop words of the segmenter
+	Stop bool
+	// MinDF the minimum number of the training texts containing the term
+	MinDF int
+}
+
+// Classifier the text classifier
+type Classifier struct {
+	Model tf.Model
+
+	opt  Options
+	vec  *vector.Vectorizer
+	df   map[string]int
+	docs int
+}
+
+// New create a new Classifier with the segmenter and the model
+func New(seg *gse.Segmenter, model tf.Model, opts ...Options) *Classifier {
+	var opt Options
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if opt.NGram <= 0 {
+		opt.NGram = 1
+	}
+
+	return &Classifier{Model: model, opt: opt,
+		vec: vector.New(seg, vector.Options{NGram: opt.NGram, Stop: opt.Stop}),
+		df:  make(map[string]int),
+	}
+}
+
+// Terms return the terms of the text
+func (c *Classifier) Terms(text string) []string {
+	return c.vec.Terms(text)
+}
+
+// Features return the features of the text,
+// the terms below the MinDF are ignored
+func (c *Classifier) Features(text string) tf.Features {
+	f := make(tf.Features)
+	for _, t := range c.Terms(text) {
+		if c.opt.MinDF > 0 && c.df[t] < c.opt.MinDF {
+			continue
+		}
+
+		if c.opt.Binary {
+			f[t] = 1
+		} else {
+			f[t]++
+		}
+	}
+
+	if c.opt.TFIDF {
+		for t, v := range f {
+			f[t] = v * (math.Log(float64(c.docs+1)/float64(c.df[t]+1)) + 1)
+		}
+	}
+
+	if c.opt.Norm && len(f) > 0 {
+		norm := 0.0
+		for _, v := range f {
+			norm += v * v
+		}
+		norm = math.Sqrt(norm)
+		for t := range f {
+			f[t] /= norm
+		}
+	}
+
+	return f
+}
+
+// Train add the samples to the document frequency and train the model
+func (c *Classifier) Train(samples []Sample) {
+	for _, s := range samples {
+		seen := make(map[string]bool)
+		for _, t := range c.Terms(s.Text) {
+			if !seen[t] {
+				seen[t] = true
+				c.df[t]++
+			}
+		}
+		c.docs++
+	}
+
+	c.Model.Fit(c.Examples(samples))
+}
+
+// TrainFile train the model by the labelled file
+func (c *Classifier) TrainFile(file string) error {
+	samples, err := LoadSamples(file)
+	if err != nil {
+		return err
+	}
+
+	c.Train(samples)
+	return nil
+}
+
+// Examples return the labelled features of the samples
+func (c *Classifier) Examples(samples []Sample) []tf.Example {
+	examples := make([]tf.Example, len(samples))
+	for i, s := range samples {
+		examples[i] = tf.Example{Features: c.Features(s.Text), Label: s.Label}
+	}
+
+	return examples
+}
+
+// Predict return the labels with the probability of the text,
+// sorted by the probability
+func (c *Classifier) Predict(text string) []tf.Result {
+	return c.Model.Predict(c.Features(text))
+}
+
+// Classify return the most probable label of the text
+func (c *Classifier) Classify(text string) string {
+	return tf.Classify(c.Model, c.Features(text))
+}
+
+// Evaluate evaluate the classifier by the samples
+func (c *Classifier) Evaluate(samples []Sample) tf.Report {
+	return tf.Test(c.Model, c.Examples(samples))
+}
+
+type saved struct {
+	Options Options         `json:"options"`
+	DF      map[string]int  `json:"df"`
+	Docs    int             `json:"docs"`
+	Model   json.RawMessage `json:"model"`
+}
+
+// WriteTo write the classifier to w
+func (c *Classifier) WriteTo(w io.Writer) (int64, error) {
+	model, err := tf.Encode(c.Model)
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := json.Marshal(saved{Options: c.opt, DF: c.df,
+		Docs: c.docs, Model: model})
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// Read read the classifier from the reader with the segmenter
+func Read(seg *gse.Segmenter, r io.Reader) (*Classifier, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var s saved
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+
+	model, err := tf.Decode(s.Model)
+	if err != nil {
+		return nil, err
+	}
+
+	c := New(seg, model, s.Options)
+	c.docs = s.Docs
+	if s.DF != nil {
+		c.df = s.DF
+	}
+	return c, nil
+}
+
+// Save write the classifier to the file
+func (c *Classifier) Save(file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.WriteTo(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Load load the classifier from the file with the segmenter
+func Load(seg *gse.Segmenter, file string) (*Classifier, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Read(seg, f)
+}
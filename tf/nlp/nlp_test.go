@@ -0,0 +1,93 @@
+package nlp
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-ego/gse"
+	"github.com/go-ego/gse/tf"
+	"github.com/vcaesar/tt"
+)
+
+const train = `sport	足球比赛今晚开始
+sport	篮球比赛的冠军
+
+sport	球队赢得冠军
+finance	股票市场大涨
+finance	银行利率下调
+finance	投资者关注股票和利率
+`
+
+func newSeg() *gse.Segmenter {
+	var seg gse.Segmenter
+	seg.SkipLog = true
+	seg.LoadDictStr(`的 1000 uj
+足球 100 n
+篮球 100 n
+比赛 100 n
+冠军 100 n
+球队 100 n
+股票 100 n
+市场 100 n
+银行 100 n
+利率 100 n
+投资者 100 n`)
+	return &seg
+}
+
+func TestReadSamples(t *testing.T) {
+	samples, err := ReadSamples(strings.NewReader(train))
+	tt.Nil(t, err)
+	tt.Equal(t, 6, len(samples))
+	tt.Equal(t, "{篮球比赛的冠军 sport}", samples[1])
+
+	_, err = ReadSamples(strings.NewReader("no label"))
+	tt.NotNil(t, err)
+}
+
+func TestClassifier(t *testing.T) {
+	samples, _ := ReadSamples(strings.NewReader(train))
+	seg := newSeg()
+
+	c := New(seg, tf.NewNaiveBayes())
+	c.Train(samples)
+	tt.Equal(t, "[足球 比赛]", c.Terms("足球比赛"))
+	tt.Equal(t, "sport", c.Classify("篮球冠军"))
+	tt.Equal(t, "finance", c.Classify("银行股票"))
+	tt.Equal(t, 1, c.Evaluate(samples).Accuracy)
+
+	c = New(seg, tf.NewLogistic(), Options{TFIDF: true, Norm: true})
+	c.Train(samples)
+	f := c.Features("足球足球")
+	tt.Equal(t, 1, f["足球"])
+	tt.Equal(t, "sport", c.Classify("足球冠军"))
+	tt.Equal(t, "finance", c.Predict("股票利率")[0].Label)
+}
+
+func TestSaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "train.txt")
+	tt.Nil(t, os.WriteFile(file, []byte(train), 0644))
+
+	seg := newSeg()
+	c := New(seg, tf.NewNaiveBayes(), Options{TFIDF: true, MinDF: 1})
+	tt.Nil(t, c.TrainFile(file))
+
+	var buf bytes.Buffer
+	_, err := c.WriteTo(&buf)
+	tt.Nil(t, err)
+
+	c1, err := Read(seg, &buf)
+	tt.Nil(t, err)
+	tt.Equal(t, c.Features("足球利率"), c1.Features("足球利率"))
+	tt.Equal(t, c.Predict("足球利率"), c1.Predict("足球利率"))
+
+	model := filepath.Join(dir, "model.json")
+	tt.Nil(t, c.Save(model))
+	c1, err = Load(seg, model)
+	tt.Nil(t, err)
+	tt.Equal(t, "sport", c1.Classify("球队"))
+}
@@ -12,4 +12,166 @@
 // License for the specific language governing permissions and limitations
 // under the License.
 
+/*
+Package tf is the classic classifiers of the sparse features,
+the multinomial Naive Bayes and the L2-regularised logistic regression,
+with the JSON model persistence and the evaluation helper.
+
+The text featurization by the gse tokens is in the tf/nlp package.
+*/
 package tf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// Features the sparse features, the feature name to the value
+type Features map[string]float64
+
+// Example the features with the label
+type Example struct {
+	Features Features
+	Label    string
+}
+
+// Result the label with the probability
+type Result struct {
+	Label string
+	Prob  float64
+}
+
+// Model the classifier model
+type Model interface {
+	// Fit train the model by the examples
+	Fit(examples []Example)
+	// Predict return the labels with the probability,
+	// sorted by the probability
+	Predict(f Features) []Result
+}
+
+// Classify return the most probable label of the features
+func Classify(m Model, f Features) string {
+	r := m.Predict(f)
+	if len(r) == 0 {
+		return ""
+	}
+	return r[0].Label
+}
+
+// results return the sorted results of the probabilities
+func results(labels []string, probs []float64) []Result {
+	r := make([]Result, len(labels))
+	for i, l := range labels {
+		r[i] = Result{Label: l, Prob: probs[i]}
+	}
+
+	sort.SliceStable(r, func(i, j int) bool {
+		return r[i].Prob > r[j].Prob
+	})
+	return r
+}
+
+// labelsOf return the sorted labels of the examples
+func labelsOf(examples []Example, labels []string) []string {
+	seen := make(map[string]bool, len(labels))
+	for _, l := range labels {
+		seen[l] = true
+	}
+
+	for _, e := range examples {
+		if !seen[e.Label] {
+			seen[e.Label] = true
+			labels = append(labels, e.Label)
+		}
+	}
+
+	sort.Strings(labels)
+	return labels
+}
+
+type saved struct {
+	Type     string      `json:"type"`
+	Bayes    *NaiveBayes `json:"bayes,omitempty"`
+	Logistic *Logistic   `json:"logistic,omitempty"`
+}
+
+// Encode return the JSON of the model with its type
+func Encode(m Model) ([]byte, error) {
+	switch m := m.(type) {
+	case *NaiveBayes:
+		return json.Marshal(saved{Type: "bayes", Bayes: m})
+	case *Logistic:
+		return json.Marshal(saved{Type: "logistic", Logistic: m})
+	}
+
+	return nil, fmt.Errorf("tf: unknown model type %T", m)
+}
+
+// Decode decode the model of the Encode
+func Decode(data []byte) (Model, error) {
+	var s saved
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case s.Type == "bayes" && s.Bayes != nil:
+		return s.Bayes, nil
+	case s.Type == "logistic" && s.Logistic != nil:
+		if err := s.Logistic.valid(); err != nil {
+			return nil, err
+		}
+		if s.Logistic.Weights == nil {
+			s.Logistic.Weights = make(map[string][]float64)
+		}
+		return s.Logistic, nil
+	}
+
+	return nil, fmt.Errorf("tf: unknown model type %q", s.Type)
+}
+
+// Write write the model to w
+func Write(w io.Writer, m Model) error {
+	data, err := Encode(m)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+// Read read the model from the reader
+func Read(r io.Reader) (Model, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return Decode(data)
+}
+
+// Save write the model to the file
+func Save(file string, m Model) error {
+	data, err := Encode(m)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, data, 0644)
+}
+
+// Load load the model from the file
+func Load(file string) (Model, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
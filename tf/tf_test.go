@@ -0,0 +1,108 @@
+package tf
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/vcaesar/tt"
+)
+
+func bow(words ...string) Features {
+	f := make(Features)
+	for _, w := range words {
+		f[w]++
+	}
+	return f
+}
+
+var examples = []Example{
+	{bow("football", "match", "goal"), "sport"},
+	{bow("basketball", "match", "team"), "sport"},
+	{bow("team", "goal", "win"), "sport"},
+	{bow("stock", "market", "bank"), "finance"},
+	{bow("bank", "rate", "loan"), "finance"},
+	{bow("market", "stock", "rate"), "finance"},
+}
+
+func TestNaiveBayes(t *testing.T) {
+	nb := NewNaiveBayes()
+	nb.Fit(examples)
+	tt.Equal(t, "[finance sport]", nb.Labels)
+	tt.Equal(t, 11, nb.Vocab)
+
+	r := nb.Predict(bow("goal", "team", "unknown"))
+	tt.Equal(t, "sport", r[0].Label)
+	tt.True(t, r[0].Prob > 0.8)
+	tt.True(t, math.Abs(r[0].Prob+r[1].Prob-1) < 1e-9)
+	tt.Equal(t, "finance", Classify(nb, bow("bank", "loan")))
+
+	// the prior of the empty features
+	tt.Equal(t, 0.5, nb.Predict(nil)[0].Prob)
+
+	// the untrained model
+	m, err := Decode([]byte(`{"type": "bayes", "bayes": {"labels": ["a", "b"]}}`))
+	tt.Nil(t, err)
+	tt.Equal(t, "[0 0]", m.(*NaiveBayes).LogProbs(bow("goal")))
+	tt.Equal(t, 0.5, m.Predict(bow("goal"))[1].Prob)
+}
+
+func TestLogistic(t *testing.T) {
+	lr := NewLogistic(LogisticOptions{Seed: 1})
+	loss := lr.Train(examples)
+	tt.True(t, loss < 0.2)
+	tt.Equal(t, "sport", Classify(lr, bow("goal", "team")))
+	tt.Equal(t, "finance", Classify(lr, bow("stock", "loan")))
+
+	lr.Fit([]Example{{bow("film", "actor"), "movie"}})
+	tt.Equal(t, "[finance movie sport]", lr.Labels)
+	tt.Equal(t, 3, len(lr.Weights["goal"]))
+	tt.Equal(t, "movie", Classify(lr, bow("actor")))
+}
+
+func TestEvaluate(t *testing.T) {
+	r := Evaluate([]string{"a", "a", "b", "b"}, []string{"a", "b", "b", "c"})
+	tt.Equal(t, 0.5, r.Accuracy)
+	tt.Equal(t, "[a b c]", r.Labels)
+	tt.Equal(t, 1, r.Classes["a"].Precision)
+	tt.Equal(t, 0.5, r.Classes["a"].Recall)
+	tt.Equal(t, 0.5, r.Classes["b"].Precision)
+	tt.Equal(t, 2, r.Classes["b"].Support)
+	tt.Equal(t, 0, r.Classes["c"].F1)
+	tt.True(t, len(r.String()) > 0)
+
+	nb := NewNaiveBayes()
+	nb.Fit(examples)
+	tt.Equal(t, 1, Test(nb, examples).Accuracy)
+}
+
+func TestEncode(t *testing.T) {
+	nb := NewNaiveBayes(0.5)
+	nb.Fit(examples)
+	lr := NewLogistic()
+	lr.Fit(examples)
+
+	for _, m := range []Model{nb, lr} {
+		var buf bytes.Buffer
+		tt.Nil(t, Write(&buf, m))
+
+		m1, err := Read(&buf)
+		tt.Nil(t, err)
+		tt.Equal(t, m.Predict(bow("goal", "bank")), m1.Predict(bow("goal", "bank")))
+	}
+
+	_, err := Decode([]byte(`{"type": "svm"}`))
+	tt.NotNil(t, err)
+
+	_, err = Decode([]byte(`{"type": "logistic", "logistic": {"labels": ["a", "b"],
+		"bias": [0, 0], "weights": {"goal": [1]}}}`))
+	tt.NotNil(t, err)
+	_, err = Decode([]byte(`{"type": "logistic", "logistic": {"labels": ["a", "b"],
+		"bias": [0]}}`))
+	tt.NotNil(t, err)
+
+	m, err := Decode([]byte(`{"type": "logistic", "logistic": {"labels": ["a", "b"],
+		"bias": [0, 1], "weights": {"goal": [2, 0]}}}`))
+	tt.Nil(t, err)
+	tt.Equal(t, "a", Classify(m, bow("goal")))
+}
@@ -39,6 +39,9 @@ type Segment struct {
 	// the bytes end of the segment in the text (not including this)
 	end int
 
+	// Position the index of the source segment, it is only set by the
+	// search mode with the Segmenter.Synonym, the synonym segments share
+	// the position of the source segment, otherwise it is 0
 	Position int
 
 	// segment information
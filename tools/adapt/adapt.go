@@ -0,0 +1,78 @@
+/*
+
+gse 词频自适应
+
+使用领域语料重新估计词典的词频 (在分词网格上使用 EM 算法),
+输出的词典可以直接使用 LoadDict 载入:
+
+go run adapt.go -dict=../../data/dict/zh/s_1.txt -corpus=corpus.txt -output=dict.txt
+
+与原词频插值：
+
+go run adapt.go -corpus=corpus.txt -lambda=0.5 -output=dict.txt
+
+*/
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/go-ego/gse"
+)
+
+var (
+	dict   = flag.String("dict", "", "词典文件, 默认为 gse 中文词典")
+	corpus = flag.String("corpus", "", "领域语料文件")
+	output = flag.String("output", "dict.txt", "输出的词典文件")
+
+	iter   = flag.Int("iter", 5, "EM 迭代次数")
+	lambda = flag.Float64("lambda", 0, "原词频的插值权重")
+)
+
+func main() {
+	flag.Parse()
+
+	var (
+		seg gse.Segmenter
+		err error
+	)
+	if *dict != "" {
+		err = seg.LoadDict(*dict)
+	} else {
+		err = seg.LoadDict()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	file, err := os.Open(*corpus)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	var texts []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		texts = append(texts, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	d := seg.Adapt(texts, gse.AdaptOptions{Iterations: *iter, Lambda: *lambda})
+
+	out, err := os.Create(*output)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer out.Close()
+
+	if _, err := d.WriteTo(out); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+
+gse 新词发现
+
+从语料中发现词典中没有的新词, 输出 gse 词典格式 (词 建议词频):
+
+go run newword.go -corpus=corpus.txt -dict=../../data/dict/zh/s_1.txt -top=100
+
+输出打分结果：
+
+go run newword.go -corpus=corpus.txt -score
+
+*/
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/go-ego/gse"
+	"github.com/go-ego/gse/discover"
+)
+
+var (
+	corpus = flag.String("corpus", "", "语料文件")
+	dict   = flag.String("dict", "", "词典文件, 过滤已有的词")
+	output = flag.String("output", "", "输出到此文件, 默认为标准输出")
+
+	top        = flag.Int("top", 100, "输出的新词数量, 0 为全部")
+	maxLen     = flag.Int("max_len", 4, "新词的最大长度")
+	minFreq    = flag.Int("min_freq", 5, "新词的最小频率")
+	minPMI     = flag.Float64("min_pmi", 1.0, "最小的点互信息")
+	minEntropy = flag.Float64("min_entropy", 1.0, "最小的左右邻字熵")
+	score      = flag.Bool("score", false, "输出频率, 点互信息, 左右熵和得分")
+)
+
+func main() {
+	flag.Parse()
+
+	var seg *gse.Segmenter
+	if *dict != "" {
+		seg = new(gse.Segmenter)
+		if err := seg.LoadDict(*dict); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	file, err := os.Open(*corpus)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	d := discover.New(seg, discover.Options{
+		MaxLen:     *maxLen,
+		MinFreq:    *minFreq,
+		MinPMI:     *minPMI,
+		MinEntropy: *minEntropy,
+	})
+	if err := d.AddReader(file); err != nil {
+		log.Fatal(err)
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		out, err = os.Create(*output)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer out.Close()
+	}
+
+	w := bufio.NewWriter(out)
+	defer w.Flush()
+	for _, word := range d.Words(*top) {
+		if *score {
+			fmt.Fprintf(w, "%s\t%d\t%.4f\t%.4f\t%.4f\t%.4f\n", word.Text, word.Freq,
+				word.PMI, word.Left, word.Right, word.Score)
+			continue
+		}
+
+		fmt.Fprintf(w, "%s %.0f\n", word.Text, word.SuggestFreq)
+	}
+}
@@ -7,6 +7,9 @@ gse 分词服务器同时提供了两种模式：
 		输入 text, freq, pos 参数
 		输出 JSON :
 			{"code":200,"text":"ok"}
+	"/save" 把字典保存到 save 参数的文件中, 未设置时不可用
+		输出 JSON :
+			{"code":200,"text":"ok"}
 	"/json"	JSON 格式的 RPC 服务
 		输入：
 			POST 或 GET 模式输入 text 参数
@@ -48,6 +51,7 @@ var (
 
 	hmm          = flag.Bool("hmm", false, "use hmm")
 	dict         = flag.String("dict", "../data/dict/dictionary.txt", "词典文件")
+	save         = flag.String("save", "", "\"/save\" 保存词典的文件, 不覆盖 dict 文件")
 	staticFolder = flag.String("static_folder", "static", "静态页面存放的目录")
 )
 
@@ -118,6 +122,19 @@ func addToken(w http.ResponseWriter, req *http.Request) {
 	io.WriteString(w, string(response))
 }
 
+func saveDict(w http.ResponseWriter, req *http.Request) {
+	resp := &Resp{Code: 200, Text: "ok"}
+	if *save == "" {
+		resp = &Resp{Code: 400, Text: "the save flag is not set"}
+	} else if err := seg.SaveDict(*save); err != nil {
+		resp = &Resp{Code: 500, Text: err.Error()}
+	}
+
+	response, _ := json.Marshal(resp)
+	w.Header().Set("Content-Type", "application/json")
+	io.WriteString(w, string(response))
+}
+
 func main() {
 	flag.Parse()
 
@@ -128,6 +145,7 @@ func main() {
 	seg.LoadDict(*dict)
 
 	http.HandleFunc("/add", addToken)
+	http.HandleFunc("/save", saveDict)
 	http.HandleFunc("/json", JsonRpcServer)
 	http.Handle("/", http.FileServer(http.Dir(*staticFolder)))
 
@@ -0,0 +1,106 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package vector
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// Result the search result
+type Result struct {
+	ID    string
+	Score float64
+}
+
+// resultHeap the min heap of the results by score
+type resultHeap []Result
+
+func (h resultHeap) Len() int { return len(h) }
+func (h resultHeap) Less(i, j int) bool {
+	if h[i].Score == h[j].Score {
+		return h[i].ID > h[j].ID
+	}
+	return h[i].Score < h[j].Score
+}
+func (h resultHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *resultHeap) Push(x interface{}) { *h = append(*h, x.(Result)) }
+func (h *resultHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// Index the brute-force nearest neighbour index of the vectors
+type Index struct {
+	ids   []string
+	vecs  []Vector
+	norms []float64
+}
+
+// NewIndex create a new empty Index
+func NewIndex() *Index {
+	return &Index{}
+}
+
+// Add add the vector with the id to the index
+func (x *Index) Add(id string, vec Vector) {
+	x.ids = append(x.ids, id)
+	x.vecs = append(x.vecs, vec)
+	x.norms = append(x.norms, vec.Norm())
+}
+
+// Len return the number of the vectors
+func (x *Index) Len() int {
+	return len(x.ids)
+}
+
+// Search return the topK most similar vectors by the cosine similarity,
+// sorted by the score, the zero similarity ones are skipped
+func (x *Index) Search(query Vector, topK int) []Result {
+	qn := query.Norm()
+	if qn == 0 || topK <= 0 {
+		return nil
+	}
+
+	h := make(resultHeap, 0, topK+1)
+	for i, vec := range x.vecs {
+		if x.norms[i] == 0 {
+			continue
+		}
+
+		score := query.Dot(vec) / (qn * x.norms[i])
+		if score <= 0 {
+			continue
+		}
+
+		heap.Push(&h, Result{ID: x.ids[i], Score: score})
+		if h.Len() > topK {
+			heap.Pop(&h)
+		}
+	}
+
+	results := []Result(h)
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score == results[j].Score {
+			return results[i].ID < results[j].ID
+		}
+		return results[i].Score > results[j].Score
+	})
+
+	return results
+}
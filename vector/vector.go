@@ -0,0 +1,293 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+/*
+Package vector is the TF-IDF and BM25 vectorizer of the gse tokens,
+the documents are turned into the sparse vectors by the corpus vocabulary,
+with the cosine similarity and the top-K nearest neighbour search.
+*/
+package vector
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/go-ego/gse"
+)
+
+// Weighting the term weighting scheme
+type Weighting int
+
+const (
+	// TFIDF the TF * IDF, IDF is log((N + 1) / (df + 1)) + 1
+	TFIDF Weighting = iota
+	// BM25 the Okapi BM25 term weight
+	BM25
+)
+
+// Options the vectorizer options
+type Options struct {
+	Weighting Weighting
+	// NGram the maximum n of the word n-grams, default is 1
+	NGram int
+	// Sublinear use 1 + log(tf) instead of the tf, only for the TFIDF
+	Sublinear bool
+	// Norm normalize the vectors to the unit L2 norm
+	Norm bool
+	// Stop remove the stop words of the segmenter
+	Stop bool
+
+	// MinDF the minimum number of the documents containing the term
+	MinDF int
+	// MaxDF the maximum ratio of the documents containing the term,
+	// default is 1.0
+	MaxDF float64
+
+	// K1 and B the BM25 parameters, default is 1.2 and 0.75
+	K1, B float64
+}
+
+// Vector the sparse vector, the indexes are sorted
+type Vector struct {
+	Index []int
+	Value []float64
+}
+
+// Vectorizer turn the documents into the sparse vectors,
+// add the corpus by Fit and get the vectors by Transform
+type Vectorizer struct {
+	seg *gse.Segmenter
+	opt Options
+
+	vocab map[string]int
+	terms []string
+	df    []int
+
+	docs     int
+	totalLen int
+}
+
+// New create a new Vectorizer with the segmenter
+func New(seg *gse.Segmenter, opts ...Options) *Vectorizer {
+	var opt Options
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	if opt.NGram <= 0 {
+		opt.NGram = 1
+	}
+	if opt.MaxDF <= 0 || opt.MaxDF > 1 {
+		opt.MaxDF = 1.0
+	}
+	if opt.K1 <= 0 {
+		opt.K1 = 1.2
+	}
+	if opt.B <= 0 || opt.B > 1 {
+		opt.B = 0.75
+	}
+
+	return &Vectorizer{seg: seg, opt: opt, vocab: make(map[string]int)}
+}
+
+func isWord(w string) bool {
+	return strings.IndexFunc(w, func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsNumber(r)
+	}) >= 0
+}
+
+// Terms return the terms of the document, the words and the n-grams
+func (v *Vectorizer) Terms(doc string) (terms []string) {
+	var words []string
+	for _, w := range v.seg.Cut(doc, true) {
+		w = strings.ToLower(strings.TrimSpace(w))
+		if isWord(w) && !(v.opt.Stop && v.seg.IsStop(w)) {
+			words = append(words, w)
+		}
+	}
+
+	for n := 1; n <= v.opt.NGram; n++ {
+		for i := 0; i+n <= len(words); i++ {
+			terms = append(terms, strings.Join(words[i:i+n], " "))
+		}
+	}
+
+	return
+}
+
+// Add add a document to the corpus vocabulary and document frequency
+func (v *Vectorizer) Add(doc string) {
+	terms := v.Terms(doc)
+	v.docs++
+	v.totalLen += len(terms)
+
+	seen := make(map[int]bool)
+	for _, t := range terms {
+		i, ok := v.vocab[t]
+		if !ok {
+			i = len(v.terms)
+			v.vocab[t] = i
+			v.terms = append(v.terms, t)
+			v.df = append(v.df, 0)
+		}
+
+		if !seen[i] {
+			seen[i] = true
+			v.df[i]++
+		}
+	}
+}
+
+// Fit add the documents to the corpus
+func (v *Vectorizer) Fit(docs []string) {
+	for _, doc := range docs {
+		v.Add(doc)
+	}
+}
+
+// Term return the term of the vector index
+func (v *Vectorizer) Term(index int) string {
+	return v.terms[index]
+}
+
+// Vocab return the index of the term and existence
+func (v *Vectorizer) Vocab(term string) (int, bool) {
+	i, ok := v.vocab[term]
+	return i, ok
+}
+
+// Docs return the number of the documents in the corpus
+func (v *Vectorizer) Docs() int {
+	return v.docs
+}
+
+// keep the term passed the min-df and max-df cuts
+func (v *Vectorizer) keep(i int) bool {
+	df := v.df[i]
+	return df >= v.opt.MinDF && float64(df) <= v.opt.MaxDF*float64(v.docs)
+}
+
+// IDF return the inverse document frequency of the term index
+func (v *Vectorizer) IDF(index int) float64 {
+	n, df := float64(v.docs), float64(v.df[index])
+	if v.opt.Weighting == BM25 {
+		return math.Log(1 + (n-df+0.5)/(df+0.5))
+	}
+
+	return math.Log((n+1)/(df+1)) + 1
+}
+
+// Transform turn the document into the sparse vector,
+// the terms not in the vocabulary are ignored
+func (v *Vectorizer) Transform(doc string) Vector {
+	terms := v.Terms(doc)
+	tf := make(map[int]float64)
+	for _, t := range terms {
+		if i, ok := v.vocab[t]; ok && v.keep(i) {
+			tf[i]++
+		}
+	}
+
+	vec := Vector{Index: make([]int, 0, len(tf)), Value: make([]float64, 0, len(tf))}
+	for i := range tf {
+		vec.Index = append(vec.Index, i)
+	}
+	sort.Ints(vec.Index)
+
+	avgLen := 1.0
+	if v.docs > 0 && v.totalLen > 0 {
+		avgLen = float64(v.totalLen) / float64(v.docs)
+	}
+
+	for _, i := range vec.Index {
+		f := tf[i]
+		switch {
+		case v.opt.Weighting == BM25:
+			k1, b := v.opt.K1, v.opt.B
+			f = f * (k1 + 1) / (f + k1*(1-b+b*float64(len(terms))/avgLen))
+		case v.opt.Sublinear:
+			f = 1 + math.Log(f)
+		}
+
+		vec.Value = append(vec.Value, f*v.IDF(i))
+	}
+
+	if v.opt.Norm {
+		vec.Normalize()
+	}
+	return vec
+}
+
+// FitTransform add the documents to the corpus and return the vectors
+func (v *Vectorizer) FitTransform(docs []string) []Vector {
+	v.Fit(docs)
+
+	vecs := make([]Vector, len(docs))
+	for i, doc := range docs {
+		vecs[i] = v.Transform(doc)
+	}
+	return vecs
+}
+
+// Dot the dot product of the two sparse vectors
+func (a Vector) Dot(b Vector) float64 {
+	dot := 0.0
+	for i, j := 0, 0; i < len(a.Index) && j < len(b.Index); {
+		switch {
+		case a.Index[i] == b.Index[j]:
+			dot += a.Value[i] * b.Value[j]
+			i++
+			j++
+		case a.Index[i] < b.Index[j]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	return dot
+}
+
+// Norm the L2 norm of the vector
+func (a Vector) Norm() float64 {
+	sum := 0.0
+	for _, v := range a.Value {
+		sum += v * v
+	}
+	return math.Sqrt(sum)
+}
+
+// Normalize scale the vector to the unit L2 norm
+func (a Vector) Normalize() {
+	norm := a.Norm()
+	if norm == 0 {
+		return
+	}
+
+	for i := range a.Value {
+		a.Value[i] /= norm
+	}
+}
+
+// Cosine the cosine similarity of the two vectors
+func Cosine(a, b Vector) float64 {
+	na, nb := a.Norm(), b.Norm()
+	if na == 0 || nb == 0 {
+		return 0
+	}
+
+	return a.Dot(b) / (na * nb)
+}
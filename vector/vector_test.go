@@ -0,0 +1,79 @@
+package vector
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-ego/gse"
+	"github.com/vcaesar/tt"
+)
+
+var docs = []string{
+	"the cat sat on the mat",
+	"the dog sat on the log",
+	"cats and dogs are friends",
+	"the stock market fell today",
+}
+
+func newSeg() *gse.Segmenter {
+	var seg gse.Segmenter
+	seg.SkipLog = true
+	seg.LoadDictStr("机器 100 n")
+	seg.LoadStopArr([]string{"the", "on", "and", "are"})
+	return &seg
+}
+
+func TestVectorizer(t *testing.T) {
+	v := New(newSeg(), Options{Stop: true, Norm: true})
+	vecs := v.FitTransform(docs)
+	tt.Equal(t, 4, v.Docs())
+	tt.Equal(t, "[cat sat mat]", v.Terms(docs[0]))
+
+	i, ok := v.Vocab("sat")
+	tt.True(t, ok)
+	tt.Equal(t, "sat", v.Term(i))
+	tt.Equal(t, math.Log(5.0/3)+1, v.IDF(i))
+
+	tt.Equal(t, 1, math.Round(vecs[0].Norm()*1e9)/1e9)
+	tt.True(t, Cosine(vecs[0], vecs[1]) > 0)
+	tt.Equal(t, 0, Cosine(vecs[0], vecs[3]))
+
+	q := v.Transform("a cat on the mat, unknown")
+	tt.Equal(t, 2, len(q.Index))
+
+	index := NewIndex()
+	for k, vec := range vecs {
+		index.Add(docs[k], vec)
+	}
+	tt.Equal(t, 4, index.Len())
+
+	res := index.Search(v.Transform("cat sat"), 2)
+	tt.Equal(t, 2, len(res))
+	tt.Equal(t, docs[0], res[0].ID)
+	tt.Equal(t, docs[1], res[1].ID)
+	tt.True(t, res[0].Score > res[1].Score)
+	tt.Equal(t, 0, len(index.Search(v.Transform("nothing"), 3)))
+}
+
+func TestVectorizerOptions(t *testing.T) {
+	v := New(newSeg(), Options{Stop: true, NGram: 2, MinDF: 2})
+	v.Fit(docs)
+	tt.Equal(t, "[dog sat log dog sat sat log]", v.Terms(docs[1]))
+
+	vec := v.Transform(docs[0])
+	tt.Equal(t, 1, len(vec.Index))
+	tt.Equal(t, "sat", v.Term(vec.Index[0]))
+
+	b := New(newSeg(), Options{Weighting: BM25, Stop: true})
+	b.Fit(docs)
+	vec = b.Transform("cat cat cat")
+	i, _ := b.Vocab("cat")
+	idf := math.Log(1 + (4-1+0.5)/(1+0.5))
+	tt.True(t, math.Abs(idf*3*2.2/(3+1.2*(0.25+0.75*3/3.25))-vec.Value[0]) < 1e-9)
+	tt.Equal(t, i, vec.Index[0])
+
+	s := New(newSeg(), Options{Sublinear: true})
+	s.Fit(docs)
+	vec = s.Transform("cat cat")
+	tt.Equal(t, (1+math.Log(2))*s.IDF(vec.Index[0]), vec.Value[0])
+}
@@ -0,0 +1,18 @@
+//go:build !race
+// +build !race
+
+package word2vec
+
+import (
+	"testing"
+
+	"github.com/vcaesar/tt"
+)
+
+// the Hogwild workers update the shared vectors without locks,
+// it is not run with the race detector
+func TestHogwild(t *testing.T) {
+	m := TrainSentences(corpus(), Options{Dim: 16, MinCount: 1, Workers: 4})
+	tt.Equal(t, 10, m.Len())
+	tt.Equal(t, 3, len(m.MostSimilar("apple", 3)))
+}
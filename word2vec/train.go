@@ -0,0 +1,369 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package word2vec
+
+import (
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode"
+
+	"github.com/go-ego/gse"
+)
+
+const (
+	maxExp    = 6
+	tableSize = 1e6
+)
+
+// Options the training options
+type Options struct {
+	// Dim the vector dimension, default is 100
+	Dim int
+	// Window the max context window, default is 5
+	Window int
+	// Negative the negative samples number, default is 5
+	Negative int
+	// MinCount the min count of the words, default is 5
+	MinCount int
+	// Epochs the training epochs, default is 5
+	Epochs int
+	// Alpha the start learning rate,
+	// default is 0.025 of the skip-gram and 0.05 of the CBOW
+	Alpha float64
+	// Sample the threshold of the frequent words down sampling,
+	// default is 1e-3, set it to negative to disable
+	Sample float64
+	// CBOW use the CBOW instead of the skip-gram
+	CBOW bool
+	// Workers the training goroutines, default is the runtime.NumCPU,
+	// the workers update the shared vectors without locks (Hogwild),
+	// use 1 worker for the reproducible result and the race detector
+	Workers int
+	// Seed the random seed
+	Seed int64
+	// Stop remove the stop words of the segmenter
+	Stop bool
+}
+
+func (opt *Options) init() {
+	if opt.Dim <= 0 {
+		opt.Dim = 100
+	}
+	if opt.Window <= 0 {
+		opt.Window = 5
+	}
+	if opt.Negative <= 0 {
+		opt.Negative = 5
+	}
+	if opt.MinCount <= 0 {
+		opt.MinCount = 5
+	}
+	if opt.Epochs <= 0 {
+		opt.Epochs = 5
+	}
+	if opt.Alpha <= 0 {
+		opt.Alpha = 0.025
+		if opt.CBOW {
+			opt.Alpha = 0.05
+		}
+	}
+	if opt.Sample == 0 {
+		opt.Sample = 1e-3
+	}
+	if opt.Workers <= 0 {
+		opt.Workers = runtime.NumCPU()
+	}
+}
+
+func isWord(w string) bool {
+	return strings.IndexFunc(w, func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsNumber(r)
+	}) >= 0
+}
+
+// Tokenize cut the documents to the sentences of the words by the segmenter,
+// the words are lower case and the punctuations are removed
+func Tokenize(seg *gse.Segmenter, docs []string, stop ...bool) [][]string {
+	sentences := make([][]string, 0, len(docs))
+	for _, doc := range docs {
+		var words []string
+		for _, w := range seg.Cut(doc, true) {
+			w = strings.ToLower(strings.TrimSpace(w))
+			if isWord(w) && !(len(stop) > 0 && stop[0] && seg.IsStop(w)) {
+				words = append(words, w)
+			}
+		}
+
+		if len(words) > 0 {
+			sentences = append(sentences, words)
+		}
+	}
+
+	return sentences
+}
+
+// Train train the model by the documents segmented by the segmenter
+func Train(seg *gse.Segmenter, docs []string, opts ...Options) *Model {
+	var opt Options
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	return TrainSentences(Tokenize(seg, docs, opt.Stop), opt)
+}
+
+// trainer the training state
+type trainer struct {
+	opt    Options
+	m      *Model
+	syn1   []float32
+	counts []int
+	table  []int32
+	// words the words number of the corpus,
+	// total the words number of all the epochs
+	words, total int64
+	done         int64
+}
+
+// TrainSentences train the model by the segmented sentences
+func TrainSentences(sentences [][]string, opts ...Options) *Model {
+	var opt Options
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	opt.init()
+
+	freqs := make(map[string]int)
+	for _, s := range sentences {
+		for _, w := range s {
+			freqs[w]++
+		}
+	}
+
+	var words []string
+	for w, n := range freqs {
+		if n >= opt.MinCount {
+			words = append(words, w)
+		}
+	}
+	// sorted by the count as the word2vec
+	sort.Slice(words, func(i, j int) bool {
+		if freqs[words[i]] == freqs[words[j]] {
+			return words[i] < words[j]
+		}
+		return freqs[words[i]] > freqs[words[j]]
+	})
+
+	t := &trainer{opt: opt, m: NewModel(words, opt.Dim)}
+	t.syn1 = make([]float32, len(t.m.vecs))
+	t.counts = make([]int, len(words))
+	for i, w := range words {
+		t.counts[i] = freqs[w]
+		t.words += int64(freqs[w])
+	}
+	if len(words) == 0 {
+		return t.m
+	}
+
+	rnd := rand.New(rand.NewSource(opt.Seed))
+	for i := range t.m.vecs {
+		t.m.vecs[i] = float32((rnd.Float64() - 0.5) / float64(opt.Dim))
+	}
+	t.initTable()
+
+	ids := make([][]int32, 0, len(sentences))
+	for _, s := range sentences {
+		var sid []int32
+		for _, w := range s {
+			if i, ok := t.m.vocab[w]; ok {
+				sid = append(sid, int32(i))
+			}
+		}
+		if len(sid) > 0 {
+			ids = append(ids, sid)
+		}
+	}
+
+	t.total = t.words * int64(opt.Epochs)
+	var wg sync.WaitGroup
+	for k := 0; k < opt.Workers; k++ {
+		wg.Add(1)
+		go func(k int) {
+			defer wg.Done()
+			t.work(ids, k)
+		}(k)
+	}
+	wg.Wait()
+
+	return t.m
+}
+
+// initTable init the unigram table of the negative sampling,
+// the probability is the count ^ 0.75
+func (t *trainer) initTable() {
+	sum := 0.0
+	for _, n := range t.counts {
+		sum += math.Pow(float64(n), 0.75)
+	}
+
+	t.table = make([]int32, int(tableSize))
+	i := 0
+	p := math.Pow(float64(t.counts[0]), 0.75) / sum
+	for k := range t.table {
+		t.table[k] = int32(i)
+		if float64(k)/tableSize > p && i < len(t.counts)-1 {
+			i++
+			p += math.Pow(float64(t.counts[i]), 0.75) / sum
+		}
+	}
+}
+
+// work train the sentences of the worker k for all the epochs
+func (t *trainer) work(sentences [][]int32, k int) {
+	opt, dim := t.opt, t.opt.Dim
+	rnd := rand.New(rand.NewSource(t.opt.Seed + int64(k) + 1))
+	neu1 := make([]float32, dim)
+	neu1e := make([]float32, dim)
+	var sen []int32
+
+	for epoch := 0; epoch < opt.Epochs; epoch++ {
+		for s := k; s < len(sentences); s += opt.Workers {
+			done := atomic.AddInt64(&t.done, int64(len(sentences[s])))
+			alpha := opt.Alpha * (1 - float64(done)/float64(t.total+1))
+			alpha = math.Max(alpha, opt.Alpha*1e-4)
+
+			// the down sampling of the frequent words
+			sen = sen[:0]
+			for _, w := range sentences[s] {
+				if opt.Sample > 0 {
+					f := float64(t.counts[w]) / float64(t.words)
+					keep := (math.Sqrt(f/opt.Sample) + 1) * opt.Sample / f
+					if keep < rnd.Float64() {
+						continue
+					}
+				}
+				sen = append(sen, w)
+			}
+
+			for i, w := range sen {
+				b := rnd.Intn(opt.Window)
+				lo, hi := i-opt.Window+b, i+opt.Window-b
+				if lo < 0 {
+					lo = 0
+				}
+				if hi > len(sen)-1 {
+					hi = len(sen) - 1
+				}
+
+				if opt.CBOW {
+					t.cbow(sen, i, lo, hi, w, alpha, rnd, neu1, neu1e)
+					continue
+				}
+
+				for c := lo; c <= hi; c++ {
+					if c == i {
+						continue
+					}
+
+					l1 := t.m.vecs[int(sen[c])*dim : int(sen[c]+1)*dim]
+					for j := range neu1e {
+						neu1e[j] = 0
+					}
+					t.negative(l1, w, alpha, rnd, neu1e)
+					for j := range l1 {
+						l1[j] += neu1e[j]
+					}
+				}
+			}
+		}
+	}
+}
+
+// cbow train the word by the average of the context vectors
+func (t *trainer) cbow(sen []int32, i, lo, hi int, w int32, alpha float64,
+	rnd *rand.Rand, neu1, neu1e []float32) {
+	dim := t.opt.Dim
+	for j := range neu1 {
+		neu1[j], neu1e[j] = 0, 0
+	}
+
+	cw := 0
+	for c := lo; c <= hi; c++ {
+		if c != i {
+			v := t.m.vecs[int(sen[c])*dim : int(sen[c]+1)*dim]
+			for j := range neu1 {
+				neu1[j] += v[j]
+			}
+			cw++
+		}
+	}
+	if cw == 0 {
+		return
+	}
+
+	for j := range neu1 {
+		neu1[j] /= float32(cw)
+	}
+	t.negative(neu1, w, alpha, rnd, neu1e)
+
+	for c := lo; c <= hi; c++ {
+		if c != i {
+			v := t.m.vecs[int(sen[c])*dim : int(sen[c]+1)*dim]
+			for j := range v {
+				v[j] += neu1e[j]
+			}
+		}
+	}
+}
+
+// negative the negative sampling of the input vector l1 and the word,
+// update the output vectors and add the input gradient to neu1e
+func (t *trainer) negative(l1 []float32, w int32, alpha float64,
+	rnd *rand.Rand, neu1e []float32) {
+	dim := t.opt.Dim
+	for d := 0; d <= t.opt.Negative; d++ {
+		target, label := w, 1.0
+		if d > 0 {
+			target = t.table[rnd.Intn(len(t.table))]
+			if target == w {
+				continue
+			}
+			label = 0
+		}
+
+		l2 := t.syn1[int(target)*dim : int(target+1)*dim]
+		f := float64(dot(l1, l2))
+		var g float64
+		switch {
+		case f > maxExp:
+			g = (label - 1) * alpha
+		case f < -maxExp:
+			g = label * alpha
+		default:
+			g = (label - 1/(1+math.Exp(-f))) * alpha
+		}
+
+		gf := float32(g)
+		for j := range l2 {
+			neu1e[j] += gf * l2[j]
+			l2[j] += gf * l1[j]
+		}
+	}
+}
@@ -0,0 +1,325 @@
+// Copyright 2016 ego authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+/*
+Package word2vec is the skip-gram and CBOW word embeddings
+of the gse tokens with the negative sampling,
+the models are saved in the word2vec text format:
+
+	model := word2vec.Train(&seg, docs, word2vec.Options{Dim: 100})
+	similar := model.MostSimilar("北京", 10)
+*/
+package word2vec
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Model the word embeddings
+type Model struct {
+	Dim   int
+	Words []string
+
+	vocab map[string]int
+	vecs  []float32
+	norms []float32
+}
+
+// Similar the similar word with the cosine similarity
+type Similar struct {
+	Word  string
+	Score float64
+}
+
+// NewModel create a new model with the zero vectors of the words
+func NewModel(words []string, dim int) *Model {
+	m := &Model{Dim: dim, Words: words,
+		vocab: make(map[string]int, len(words)),
+		vecs:  make([]float32, len(words)*dim),
+	}
+	for i, w := range words {
+		m.vocab[w] = i
+	}
+
+	return m
+}
+
+// Len return the number of the words
+func (m *Model) Len() int {
+	return len(m.Words)
+}
+
+// Index return the index of the word and existence
+func (m *Model) Index(word string) (int, bool) {
+	i, ok := m.vocab[word]
+	return i, ok
+}
+
+// Vector return the vector of the word, do not modify it
+func (m *Model) Vector(word string) ([]float32, bool) {
+	i, ok := m.vocab[word]
+	if !ok {
+		return nil, false
+	}
+	return m.vecs[i*m.Dim : (i+1)*m.Dim], true
+}
+
+// SetVector set the vector of the word, the word must be in the model
+func (m *Model) SetVector(word string, vec []float32) bool {
+	i, ok := m.vocab[word]
+	if ok {
+		copy(m.vecs[i*m.Dim:(i+1)*m.Dim], vec)
+		m.norms = nil
+	}
+	return ok
+}
+
+func norm(v []float32) float32 {
+	s := 0.0
+	for _, x := range v {
+		s += float64(x) * float64(x)
+	}
+	return float32(math.Sqrt(s))
+}
+
+func dot(a, b []float32) (s float32) {
+	for i := range a {
+		s += a[i] * b[i]
+	}
+	return
+}
+
+// calcNorms calculate the vector norms for the queries
+func (m *Model) calcNorms() {
+	if len(m.norms) == len(m.Words) {
+		return
+	}
+
+	m.norms = make([]float32, len(m.Words))
+	for i := range m.Words {
+		m.norms[i] = norm(m.vecs[i*m.Dim : (i+1)*m.Dim])
+	}
+}
+
+// Similarity return the cosine similarity of the two words
+func (m *Model) Similarity(a, b string) float64 {
+	va, ok1 := m.Vector(a)
+	vb, ok2 := m.Vector(b)
+	if !ok1 || !ok2 {
+		return 0
+	}
+
+	n := norm(va) * norm(vb)
+	if n == 0 {
+		return 0
+	}
+	return float64(dot(va, vb) / n)
+}
+
+// Nearest return the k words most similar to the vector,
+// sorted by the cosine similarity, the exclude words are skipped
+func (m *Model) Nearest(vec []float32, k int, exclude ...string) []Similar {
+	vn := norm(vec)
+	if vn == 0 || k <= 0 {
+		return nil
+	}
+	m.calcNorms()
+
+	skip := make(map[string]bool, len(exclude))
+	for _, w := range exclude {
+		skip[w] = true
+	}
+
+	var result []Similar
+	for i, w := range m.Words {
+		if skip[w] || m.norms[i] == 0 {
+			continue
+		}
+
+		score := float64(dot(vec, m.vecs[i*m.Dim:(i+1)*m.Dim]) / (vn * m.norms[i]))
+		if len(result) == k && score <= result[k-1].Score {
+			continue
+		}
+
+		// insert sort of the top k
+		j := sort.Search(len(result), func(j int) bool { return result[j].Score < score })
+		if len(result) < k {
+			result = append(result, Similar{})
+		}
+		copy(result[j+1:], result[j:])
+		result[j] = Similar{Word: w, Score: score}
+	}
+
+	return result
+}
+
+// MostSimilar return the k words most similar to the word
+func (m *Model) MostSimilar(word string, k int) []Similar {
+	vec, ok := m.Vector(word)
+	if !ok {
+		return nil
+	}
+	return m.Nearest(vec, k, word)
+}
+
+// Analogy return the k words of the analogy a : b = c : ?,
+// such as "man" : "king" = "woman" : "queen", by the vector b - a + c
+func (m *Model) Analogy(a, b, c string, k int) []Similar {
+	vec := make([]float32, m.Dim)
+	for i, w := range []string{a, b, c} {
+		v, ok := m.Vector(w)
+		if !ok {
+			return nil
+		}
+
+		n := norm(v)
+		if n == 0 {
+			continue
+		}
+		sign := float32(1)
+		if i == 0 {
+			sign = -1
+		}
+		for j := range vec {
+			vec[j] += sign * v[j] / n
+		}
+	}
+
+	return m.Nearest(vec, k, a, b, c)
+}
+
+// Read read the model in the word2vec text format, the first line is
+// the words number and the dimension, then the word and its vector per line
+func Read(r io.Reader) (*Model, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	var size, dim int
+	if _, err := fmt.Sscan(scanner.Text(), &size, &dim); err != nil {
+		return nil, fmt.Errorf("word2vec: invalid header %q", scanner.Text())
+	}
+	if size < 0 {
+		return nil, fmt.Errorf("word2vec: invalid words number %d", size)
+	}
+	if dim <= 0 {
+		return nil, fmt.Errorf("word2vec: invalid dimension %d", dim)
+	}
+
+	// not trust the header for the large preallocation
+	n, nv := size, 0
+	if n > 1<<16 {
+		n = 1 << 16
+	}
+	if dim <= 1<<16 {
+		nv = n * dim
+	}
+	words := make([]string, 0, n)
+	vecs := make([]float32, 0, nv)
+	for line := 2; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != dim+1 {
+			return nil, fmt.Errorf("word2vec: line %d: expect %d values, got %d",
+				line, dim, len(fields)-1)
+		}
+
+		words = append(words, fields[0])
+		for _, f := range fields[1:] {
+			v, err := strconv.ParseFloat(f, 32)
+			if err != nil {
+				return nil, fmt.Errorf("word2vec: line %d: %v", line, err)
+			}
+			vecs = append(vecs, float32(v))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(words) != size {
+		return nil, fmt.Errorf("word2vec: expect %d words, got %d", size, len(words))
+	}
+
+	m := NewModel(words, dim)
+	m.vecs = vecs
+	return m, nil
+}
+
+// Load load the model from the word2vec text file
+func Load(file string) (*Model, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+// WriteTo write the model to w in the word2vec text format
+func (m *Model) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	n, err := fmt.Fprintf(bw, "%d %d\n", len(m.Words), m.Dim)
+	total := int64(n)
+	if err != nil {
+		return total, err
+	}
+
+	buf := make([]byte, 0, 16)
+	for i, word := range m.Words {
+		n, _ = bw.WriteString(word)
+		total += int64(n)
+		for _, v := range m.vecs[i*m.Dim : (i+1)*m.Dim] {
+			buf = append(buf[:0], ' ')
+			buf = strconv.AppendFloat(buf, float64(v), 'f', 6, 32)
+			n, _ = bw.Write(buf)
+			total += int64(n)
+		}
+
+		if err = bw.WriteByte('\n'); err != nil {
+			return total, err
+		}
+		total++
+	}
+
+	return total, bw.Flush()
+}
+
+// Save write the model to the file in the word2vec text format
+func (m *Model) Save(file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.WriteTo(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
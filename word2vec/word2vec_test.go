@@ -0,0 +1,109 @@
+package word2vec
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/go-ego/gse"
+	"github.com/vcaesar/tt"
+)
+
+var topics = [][]string{
+	{"apple", "banana", "orange", "fruit", "juice"},
+	{"car", "bus", "train", "road", "drive"},
+}
+
+func corpus() (sentences [][]string) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 400; i++ {
+		topic := topics[i%2]
+		var s []string
+		for j := 0; j < 6; j++ {
+			s = append(s, topic[rnd.Intn(len(topic))])
+		}
+		sentences = append(sentences, s)
+	}
+	return
+}
+
+func topicOf(word string) int {
+	for i, t := range topics {
+		for _, w := range t {
+			if w == word {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func TestTrain(t *testing.T) {
+	for _, cbow := range []bool{false, true} {
+		m := TrainSentences(corpus(), Options{Dim: 16, Window: 3, MinCount: 1,
+			Epochs: 5, Sample: -1, CBOW: cbow, Workers: 1, Seed: 1})
+		tt.Equal(t, 10, m.Len())
+
+		similar := m.MostSimilar("apple", 4)
+		tt.Equal(t, 4, len(similar))
+		for _, s := range similar {
+			tt.Equal(t, 0, topicOf(s.Word))
+		}
+		tt.True(t, similar[0].Score >= similar[3].Score)
+		tt.True(t, m.Similarity("car", "bus") > m.Similarity("car", "apple"))
+	}
+
+	m := TrainSentences(corpus(), Options{Dim: 16, MinCount: 1, Workers: 1})
+	tt.Equal(t, 10, m.Len())
+	tt.Equal(t, 0, len(m.MostSimilar("unknown", 3)))
+
+	a := m.Analogy("apple", "banana", "car", 3)
+	tt.Equal(t, 3, len(a))
+	for _, s := range a {
+		tt.True(t, s.Word != "apple" && s.Word != "banana" && s.Word != "car")
+	}
+}
+
+func TestSegTrain(t *testing.T) {
+	var seg gse.Segmenter
+	seg.SkipLog = true
+	seg.LoadDictStr("机器 100 n\n学习 100 v\n平台 100 n")
+
+	docs := []string{"机器学习平台", "Machine 学习, 机器!"}
+	tt.Equal(t, "[[机器 学习 平台] [machine 学习 机器]]", Tokenize(&seg, docs))
+
+	m := Train(&seg, docs, Options{Dim: 8, MinCount: 2, Workers: 1})
+	tt.Equal(t, "[学习 机器]", m.Words)
+}
+
+func TestReadWrite(t *testing.T) {
+	m := NewModel([]string{"a", "b", "c"}, 2)
+	m.SetVector("a", []float32{1, 0})
+	m.SetVector("b", []float32{0.5, 0.5})
+	m.SetVector("c", []float32{-1, 0.25})
+
+	var buf bytes.Buffer
+	n, err := m.WriteTo(&buf)
+	tt.Nil(t, err)
+	tt.Equal(t, buf.Len(), n)
+	tt.Equal(t, "3 2\na 1.000000 0.000000\n", buf.String()[:24])
+
+	m1, err := Read(&buf)
+	tt.Nil(t, err)
+	tt.Equal(t, m.Words, m1.Words)
+	v, ok := m1.Vector("c")
+	tt.True(t, ok)
+	tt.Equal(t, "[-1 0.25]", v)
+	tt.Equal(t, "b", m1.MostSimilar("a", 1)[0].Word)
+
+	_, err = Read(strings.NewReader("1 2\na 1\n"))
+	tt.NotNil(t, err)
+
+	_, err = Read(strings.NewReader("-1 2\na 1 2\n"))
+	tt.NotNil(t, err)
+	_, err = Read(strings.NewReader("2 2\na 1 2\n"))
+	tt.NotNil(t, err)
+	_, err = Read(strings.NewReader("99999999999 99999999999\na 1 2\n"))
+	tt.NotNil(t, err)
+}